@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// annotation adds tags to the tests that it matches, or marks them
+// as skipped or expected to fail, e.g. because of a known bug in
+// the API.
+type annotation struct {
+	// Suite, Element and ID select the tests that the annotation
+	// applies to. Each is a pattern as for path.Match, and an
+	// empty one matches anything.
+	Suite   string `json:"suite"`
+	Element string `json:"element"`
+	ID      string `json:"id"`
+
+	// Tags are added to the tests' own tags.
+	Tags []string `json:"tags"`
+
+	// Skip means the tests are not run.
+	Skip bool `json:"skip"`
+
+	// XFail means the tests are expected to fail.
+	XFail bool `json:"xfail"`
+
+	// Reason explains a skip or expected failure.
+	Reason string `json:"reason"`
+
+	// Issue links to the bug report, if any.
+	Issue string `json:"issue"`
+}
+
+// readAnnotations reads the annotations from a file.
+func readAnnotations(filename string) ([]*annotation, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var as []*annotation
+	err = json.Unmarshal(b, &as)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	for _, a := range as {
+		for _, p := range []string{a.Suite, a.Element, a.ID} {
+			_, err = path.Match(p, "")
+			if err != nil {
+				return nil, fmt.Errorf("error in %s: bad pattern %q: %v", filename, p, err)
+			}
+		}
+		if (a.Skip || a.XFail) && a.Reason == "" {
+			return nil, fmt.Errorf("error in %s: %s/%s/%s is skipped or expected to fail without a reason", filename, a.Suite, a.Element, a.ID)
+		}
+	}
+
+	return as, nil
+}
+
+// matches returns whether the annotation applies to a test.
+func (a *annotation) matches(r *testresult.TestResult) bool {
+	return matchField(a.Suite, r.Suite) && matchField(a.Element, r.Element) && matchField(a.ID, r.ID)
+}
+
+// matchField matches one of an annotation's patterns. The patterns
+// have been checked by readAnnotations.
+func matchField(pattern string, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	ok, _ := path.Match(pattern, s)
+	return ok
+}
+
+// annotate applies the matching annotations to a test's result, or
+// to its probed names before it is run.
+func annotate(r *testresult.TestResult, as []*annotation) {
+	for _, a := range as {
+		if !a.matches(r) {
+			continue
+		}
+		for _, t := range a.Tags {
+			if !r.HasTag(t) {
+				r.Tags = append(r.Tags, t)
+			}
+		}
+		if a.Skip {
+			r.Skipped = true
+		}
+		if a.XFail {
+			r.XFail = true
+		}
+		if a.Skip || a.XFail {
+			r.Reason = a.Reason
+			r.Issue = a.Issue
+		}
+	}
+}
+
+// selected returns whether a test should be run, given the tags
+// to select (if any) and the tags to exclude.
+func selected(r *testresult.TestResult, include []string, exclude []string) bool {
+	for _, t := range exclude {
+		if r.HasTag(t) {
+			return false
+		}
+	}
+	if len(include) == 0 {
+		return true
+	}
+	for _, t := range include {
+		if r.HasTag(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeTests learns the names and tags that each test reports, so
+// that tests can be selected, named and annotated before they are
+// run. Each test is run once against a server that fails every
+// request, which makes it stop at its first step, after it has
+// filled in its names.
+func probeTests(tests []testresult.TestFunc) []*testresult.TestResult {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	rs := []*testresult.TestResult{}
+	for _, t := range tests {
+		r := t(srv.URL)
+		rs = append(rs, &testresult.TestResult{
+			Suite:   r.Suite,
+			Element: r.Element,
+			ID:      r.ID,
+			Tags:    append([]string{}, r.Tags...),
+		})
+	}
+	return rs
+}
+
+// anySkipped returns whether any of the annotations skip tests.
+func anySkipped(as []*annotation) bool {
+	for _, a := range as {
+		if a.Skip {
+			return true
+		}
+	}
+	return false
+}
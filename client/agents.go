@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListAgents returns all agents.
+func (c *Client) ListAgents() ([]*Agent, error) {
+	var out struct {
+		Agents []*Agent `json:"agents"`
+	}
+	err := c.do("GET", "/agents", nil, http.StatusOK, &out)
+	return out.Agents, err
+}
+
+// CreateAgent creates a new agent and returns its ID.
+func (c *Client) CreateAgent(a *Agent) (uint32, error) {
+	var out created
+	err := c.do("POST", "/agents", a, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetAgent returns the agent with the given ID.
+func (c *Client) GetAgent(id uint32) (*Agent, error) {
+	var out struct {
+		Agent *Agent `json:"agent"`
+	}
+	err := c.do("GET", fmt.Sprintf("/agents/%d", id), nil, http.StatusOK, &out)
+	return out.Agent, err
+}
+
+// UpdateAgent changes the given fields of an agent.
+func (c *Client) UpdateAgent(id uint32, a *AgentUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/agents/%d", id), a, http.StatusNoContent, nil)
+}
+
+// DeleteAgent deletes an agent.
+func (c *Client) DeleteAgent(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/agents/%d", id), nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,153 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package client is a typed Go client for the peridot API.
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// Identity adds credentials to a request before it is sent.
+type Identity interface {
+	Authorize(req *http.Request) error
+}
+
+// IdentityFunc adapts an ordinary function to an Identity.
+type IdentityFunc func(req *http.Request) error
+
+// Authorize calls f(req).
+func (f IdentityFunc) Authorize(req *http.Request) error {
+	return f(req)
+}
+
+// Token is an Identity that sends a JWT as a bearer token.
+type Token string
+
+// Authorize sets the Authorization header to the bearer token.
+func (t Token) Authorize(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// Anonymous is an Identity that sends no credentials.
+var Anonymous Identity = IdentityFunc(func(req *http.Request) error { return nil })
+
+// Client makes calls to the peridot API as a particular identity.
+type Client struct {
+	// Root is the root URL of the API, e.g. "http://sut:3005".
+	Root string
+
+	// Identity provides the credentials sent with each request.
+	Identity Identity
+
+	// HTTPClient is used to send requests. If nil, a default
+	// client is used.
+	HTTPClient *http.Client
+}
+
+// New creates a client for the API at root, acting as id.
+func New(root string, id Identity) *Client {
+	return &Client{
+		Root:     root,
+		Identity: id,
+	}
+}
+
+// As returns a copy of the client that acts as a different
+// identity.
+func (c *Client) As(id Identity) *Client {
+	c2 := *c
+	c2.Identity = id
+	return &c2
+}
+
+// Hello calls the unauthenticated /hello endpoint, and returns
+// its message.
+func (c *Client) Hello() (string, error) {
+	var out struct {
+		Message string `json:"message"`
+	}
+	err := c.do("GET", "/hello", nil, http.StatusOK, &out)
+	return out.Message, err
+}
+
+// ResetDB asks the API to reset its database to the initial
+// state, with only the initial admin user.
+func (c *Client) ResetDB() error {
+	return c.AdminDB("resetDB")
+}
+
+// AdminDB sends a command to the /admin/db endpoint.
+func (c *Client) AdminDB(command string) error {
+	in := map[string]string{"command": command}
+	return c.do("POST", "/admin/db", in, http.StatusNoContent, nil)
+}
+
+// created is the response body for a successful POST.
+type created struct {
+	ID uint32 `json:"id"`
+}
+
+// do sends a request with in (if not nil) marshalled as the JSON
+// body, checks for the wanted status code, and unmarshals the
+// response body into out (if not nil). A response with any other
+// status code is returned as an *APIError.
+func (c *Client) do(method string, path string, in interface{}, wantCode int, out interface{}) error {
+	url := c.Root + path
+
+	var body io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("could not encode request for %s %s: %v", method, url, err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Identity != nil {
+		err = c.Identity.Authorize(req)
+		if err != nil {
+			return err
+		}
+	}
+
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode != wantCode {
+		return newAPIError(method, url, resp.StatusCode, b)
+	}
+
+	if out != nil {
+		err = json.Unmarshal(b, out)
+		if err != nil {
+			return fmt.Errorf("could not decode response from %s %s: %v", method, url, err)
+		}
+	}
+
+	return nil
+}
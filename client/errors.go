@@ -0,0 +1,85 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// APIError is returned when the API responds with a status code
+// other than the one expected for a successful call.
+type APIError struct {
+	// Method and URL identify the request that failed.
+	Method string
+	URL    string
+
+	// StatusCode is the HTTP status code that was returned.
+	StatusCode int
+
+	// Message is the "error" string from the response body, if
+	// there was one.
+	Message string
+
+	// Body is the raw response body.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("%s %s: HTTP %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("%s %s: HTTP %d", e.Method, e.URL, e.StatusCode)
+}
+
+// newAPIError builds an APIError, taking the message from the
+// response body if it is an error envelope.
+func newAPIError(method string, url string, code int, body []byte) *APIError {
+	e := &APIError{
+		Method:     method,
+		URL:        url,
+		StatusCode: code,
+		Body:       body,
+	}
+
+	var envelope struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(body, &envelope) == nil {
+		e.Message = envelope.Error
+	}
+
+	return e
+}
+
+// StatusCode returns the HTTP status code of err if it is (or
+// wraps) an *APIError, or 0 otherwise.
+func StatusCode(err error) int {
+	var e *APIError
+	if errors.As(err, &e) {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsBadRequest indicates whether err is an API 400 response.
+func IsBadRequest(err error) bool {
+	return StatusCode(err) == http.StatusBadRequest
+}
+
+// IsUnauthorized indicates whether err is an API 401 response.
+func IsUnauthorized(err error) bool {
+	return StatusCode(err) == http.StatusUnauthorized
+}
+
+// IsAccessDenied indicates whether err is an API 403 response.
+func IsAccessDenied(err error) bool {
+	return StatusCode(err) == http.StatusForbidden
+}
+
+// IsNotFound indicates whether err is an API 404 response.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
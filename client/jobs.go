@@ -0,0 +1,56 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListJobs returns the jobs for a repopull.
+func (c *Client) ListJobs(repoPullID uint32) ([]*Job, error) {
+	var out struct {
+		Jobs []*Job `json:"jobs"`
+	}
+	err := c.do("GET", fmt.Sprintf("/repopulls/%d/jobs", repoPullID), nil, http.StatusOK, &out)
+	return out.Jobs, err
+}
+
+// CreateJob creates a new job for a repopull, and returns its ID.
+// Only the AgentID, PriorJobIDs, IsReady and Config fields of j
+// are used.
+func (c *Client) CreateJob(repoPullID uint32, j *Job) (uint32, error) {
+	// priorjob_ids is always sent, even if empty
+	in := struct {
+		AgentID     uint32    `json:"agent_id"`
+		PriorJobIDs []uint32  `json:"priorjob_ids"`
+		IsReady     bool      `json:"is_ready"`
+		Config      JobConfig `json:"config"`
+	}{j.AgentID, j.PriorJobIDs, j.IsReady, j.Config}
+	if in.PriorJobIDs == nil {
+		in.PriorJobIDs = []uint32{}
+	}
+
+	var out created
+	err := c.do("POST", fmt.Sprintf("/repopulls/%d/jobs", repoPullID), in, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetJob returns the job with the given ID.
+func (c *Client) GetJob(id uint32) (*Job, error) {
+	var out struct {
+		Job *Job `json:"job"`
+	}
+	err := c.do("GET", fmt.Sprintf("/jobs/%d", id), nil, http.StatusOK, &out)
+	return out.Job, err
+}
+
+// UpdateJob changes the given fields of a job.
+func (c *Client) UpdateJob(id uint32, j *JobUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/jobs/%d", id), j, http.StatusNoContent, nil)
+}
+
+// DeleteJob deletes a job.
+func (c *Client) DeleteJob(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/jobs/%d", id), nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,43 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListProjects returns all projects.
+func (c *Client) ListProjects() ([]*Project, error) {
+	var out struct {
+		Projects []*Project `json:"projects"`
+	}
+	err := c.do("GET", "/projects", nil, http.StatusOK, &out)
+	return out.Projects, err
+}
+
+// CreateProject creates a new project and returns its ID.
+func (c *Client) CreateProject(p *Project) (uint32, error) {
+	var out created
+	err := c.do("POST", "/projects", p, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetProject returns the project with the given ID.
+func (c *Client) GetProject(id uint32) (*Project, error) {
+	var out struct {
+		Project *Project `json:"project"`
+	}
+	err := c.do("GET", fmt.Sprintf("/projects/%d", id), nil, http.StatusOK, &out)
+	return out.Project, err
+}
+
+// UpdateProject changes the given fields of a project.
+func (c *Client) UpdateProject(id uint32, p *ProjectUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/projects/%d", id), p, http.StatusNoContent, nil)
+}
+
+// DeleteProject deletes a project.
+func (c *Client) DeleteProject(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/projects/%d", id), nil, http.StatusNoContent, nil)
+}
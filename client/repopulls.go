@@ -0,0 +1,39 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListRepoPulls returns the repopulls for a branch of a repo.
+func (c *Client) ListRepoPulls(repoID uint32, branch string) ([]*RepoPull, error) {
+	var out struct {
+		Pulls []*RepoPull `json:"pulls"`
+	}
+	err := c.do("GET", branchPath(repoID, branch), nil, http.StatusOK, &out)
+	return out.Pulls, err
+}
+
+// CreateRepoPull creates a new repopull for a branch of a repo,
+// and returns its ID.
+func (c *Client) CreateRepoPull(repoID uint32, branch string, pr *PullRequest) (uint32, error) {
+	var out created
+	err := c.do("POST", branchPath(repoID, branch), pr, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetRepoPull returns the repopull with the given ID.
+func (c *Client) GetRepoPull(id uint32) (*RepoPull, error) {
+	var out struct {
+		RepoPull *RepoPull `json:"repopull"`
+	}
+	err := c.do("GET", fmt.Sprintf("/repopulls/%d", id), nil, http.StatusOK, &out)
+	return out.RepoPull, err
+}
+
+// DeleteRepoPull deletes a repopull.
+func (c *Client) DeleteRepoPull(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/repopulls/%d", id), nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,75 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// ListRepos returns all repos.
+func (c *Client) ListRepos() ([]*Repo, error) {
+	var out struct {
+		Repos []*Repo `json:"repos"`
+	}
+	err := c.do("GET", "/repos", nil, http.StatusOK, &out)
+	return out.Repos, err
+}
+
+// ListSubprojectRepos returns the repos of a subproject.
+func (c *Client) ListSubprojectRepos(subprojectID uint32) ([]*Repo, error) {
+	var out struct {
+		Repos []*Repo `json:"repos"`
+	}
+	err := c.do("GET", fmt.Sprintf("/subprojects/%d/repos", subprojectID), nil, http.StatusOK, &out)
+	return out.Repos, err
+}
+
+// CreateRepo creates a new repo in the subproject given by its
+// SubprojectID, and returns its ID.
+func (c *Client) CreateRepo(r *Repo) (uint32, error) {
+	var out created
+	err := c.do("POST", "/repos", r, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetRepo returns the repo with the given ID.
+func (c *Client) GetRepo(id uint32) (*Repo, error) {
+	var out struct {
+		Repo *Repo `json:"repo"`
+	}
+	err := c.do("GET", fmt.Sprintf("/repos/%d", id), nil, http.StatusOK, &out)
+	return out.Repo, err
+}
+
+// UpdateRepo changes the given fields of a repo.
+func (c *Client) UpdateRepo(id uint32, r *RepoUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/repos/%d", id), r, http.StatusNoContent, nil)
+}
+
+// DeleteRepo deletes a repo.
+func (c *Client) DeleteRepo(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/repos/%d", id), nil, http.StatusNoContent, nil)
+}
+
+// ListRepoBranches returns the names of a repo's branches.
+func (c *Client) ListRepoBranches(repoID uint32) ([]string, error) {
+	var out struct {
+		Branches []string `json:"branches"`
+	}
+	err := c.do("GET", fmt.Sprintf("/repos/%d/branches", repoID), nil, http.StatusOK, &out)
+	return out.Branches, err
+}
+
+// CreateRepoBranch adds a branch to a repo.
+func (c *Client) CreateRepoBranch(repoID uint32, branch string) error {
+	in := map[string]string{"branch": branch}
+	return c.do("POST", fmt.Sprintf("/repos/%d/branches", repoID), in, http.StatusCreated, nil)
+}
+
+// branchPath returns the path for a repo branch, escaping the
+// branch name.
+func branchPath(repoID uint32, branch string) string {
+	return fmt.Sprintf("/repos/%d/branches/%s", repoID, url.PathEscape(branch))
+}
@@ -0,0 +1,53 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListSubprojects returns all subprojects.
+func (c *Client) ListSubprojects() ([]*Subproject, error) {
+	var out struct {
+		Subprojects []*Subproject `json:"subprojects"`
+	}
+	err := c.do("GET", "/subprojects", nil, http.StatusOK, &out)
+	return out.Subprojects, err
+}
+
+// ListProjectSubprojects returns the subprojects of a project.
+func (c *Client) ListProjectSubprojects(projectID uint32) ([]*Subproject, error) {
+	var out struct {
+		Subprojects []*Subproject `json:"subprojects"`
+	}
+	err := c.do("GET", fmt.Sprintf("/projects/%d/subprojects", projectID), nil, http.StatusOK, &out)
+	return out.Subprojects, err
+}
+
+// CreateSubproject creates a new subproject in the project given
+// by its ProjectID, and returns its ID.
+func (c *Client) CreateSubproject(s *Subproject) (uint32, error) {
+	var out created
+	err := c.do("POST", "/subprojects", s, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetSubproject returns the subproject with the given ID.
+func (c *Client) GetSubproject(id uint32) (*Subproject, error) {
+	var out struct {
+		Subproject *Subproject `json:"subproject"`
+	}
+	err := c.do("GET", fmt.Sprintf("/subprojects/%d", id), nil, http.StatusOK, &out)
+	return out.Subproject, err
+}
+
+// UpdateSubproject changes the given fields of a subproject.
+func (c *Client) UpdateSubproject(id uint32, s *SubprojectUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/subprojects/%d", id), s, http.StatusNoContent, nil)
+}
+
+// DeleteSubproject deletes a subproject.
+func (c *Client) DeleteSubproject(id uint32) error {
+	return c.do("DELETE", fmt.Sprintf("/subprojects/%d", id), nil, http.StatusNoContent, nil)
+}
@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"time"
+)
+
+// User is a peridot user. Callers without admin access only see
+// the ID and Github fields of other users.
+type User struct {
+	ID     uint32 `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Github string `json:"github"`
+	Access string `json:"access,omitempty"`
+}
+
+// UserUpdate holds the fields to change on a user. Empty fields
+// are left unchanged.
+type UserUpdate struct {
+	Name   string `json:"name,omitempty"`
+	Github string `json:"github,omitempty"`
+	Access string `json:"access,omitempty"`
+}
+
+// Project is a top-level project.
+type Project struct {
+	ID       uint32 `json:"id,omitempty"`
+	Name     string `json:"name"`
+	Fullname string `json:"fullname"`
+}
+
+// ProjectUpdate holds the fields to change on a project. Empty
+// fields are left unchanged.
+type ProjectUpdate struct {
+	Name     string `json:"name,omitempty"`
+	Fullname string `json:"fullname,omitempty"`
+}
+
+// Subproject is a subproject within a project.
+type Subproject struct {
+	ID        uint32 `json:"id,omitempty"`
+	ProjectID uint32 `json:"project_id"`
+	Name      string `json:"name"`
+	Fullname  string `json:"fullname"`
+}
+
+// SubprojectUpdate holds the fields to change on a subproject.
+// Empty fields are left unchanged.
+type SubprojectUpdate struct {
+	ProjectID uint32 `json:"project_id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Fullname  string `json:"fullname,omitempty"`
+}
+
+// Repo is a code repository within a subproject.
+type Repo struct {
+	ID           uint32 `json:"id,omitempty"`
+	SubprojectID uint32 `json:"subproject_id"`
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+}
+
+// RepoUpdate holds the fields to change on a repo. Empty fields
+// are left unchanged.
+type RepoUpdate struct {
+	SubprojectID uint32 `json:"subproject_id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Address      string `json:"address,omitempty"`
+}
+
+// RepoPull is a request to pull a particular commit or tag of a
+// repo branch.
+type RepoPull struct {
+	ID         uint32    `json:"id"`
+	RepoID     uint32    `json:"repo_id"`
+	Branch     string    `json:"branch"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Status     string    `json:"status"`
+	Health     string    `json:"health"`
+	Output     string    `json:"output,omitempty"`
+	Commit     string    `json:"commit"`
+	Tag        string    `json:"tag,omitempty"`
+	SPDXID     string    `json:"spdx_id"`
+}
+
+// PullRequest holds what to pull when creating a repopull. If both
+// fields are empty, the head of the branch is pulled.
+type PullRequest struct {
+	Commit string `json:"commit,omitempty"`
+	Tag    string `json:"tag,omitempty"`
+}
+
+// Agent is a peridot agent that can run jobs.
+type Agent struct {
+	ID           uint32 `json:"id,omitempty"`
+	Name         string `json:"name"`
+	IsActive     bool   `json:"is_active"`
+	Address      string `json:"address"`
+	Port         int    `json:"port"`
+	IsCodeReader bool   `json:"is_codereader"`
+	IsSpdxReader bool   `json:"is_spdxreader"`
+	IsCodeWriter bool   `json:"is_codewriter"`
+	IsSpdxWriter bool   `json:"is_spdxwriter"`
+}
+
+// AgentUpdate holds the fields to change on an agent. Nil and
+// empty fields are left unchanged.
+type AgentUpdate struct {
+	IsActive     *bool  `json:"is_active,omitempty"`
+	Address      string `json:"address,omitempty"`
+	Port         int    `json:"port,omitempty"`
+	IsCodeReader *bool  `json:"is_codereader,omitempty"`
+	IsSpdxReader *bool  `json:"is_spdxreader,omitempty"`
+	IsCodeWriter *bool  `json:"is_codewriter,omitempty"`
+	IsSpdxWriter *bool  `json:"is_spdxwriter,omitempty"`
+}
+
+// Job is a single run of an agent for a repopull.
+type Job struct {
+	ID          uint32    `json:"id,omitempty"`
+	RepoPullID  uint32    `json:"repopull_id,omitempty"`
+	AgentID     uint32    `json:"agent_id"`
+	PriorJobIDs []uint32  `json:"priorjob_ids,omitempty"`
+	StartedAt   time.Time `json:"started_at"`
+	FinishedAt  time.Time `json:"finished_at"`
+	Status      string    `json:"status,omitempty"`
+	Health      string    `json:"health,omitempty"`
+	Output      string    `json:"output,omitempty"`
+	IsReady     bool      `json:"is_ready"`
+	Config      JobConfig `json:"config"`
+}
+
+// JobConfig is the configuration given to a job's agent.
+type JobConfig struct {
+	KV         map[string]string        `json:"kv,omitempty"`
+	CodeReader map[string]JobPathConfig `json:"codereader,omitempty"`
+	SpdxReader map[string]JobPathConfig `json:"spdxreader,omitempty"`
+}
+
+// JobPathConfig says where an agent should read its input from:
+// either a path, or the output of a prior job.
+type JobPathConfig struct {
+	Path       string `json:"path,omitempty"`
+	PriorJobID uint32 `json:"priorjob_id,omitempty"`
+}
+
+// JobUpdate holds the fields to change on a job. Nil fields are
+// left unchanged.
+type JobUpdate struct {
+	IsReady *bool `json:"is_ready,omitempty"`
+}
+
+// Bool returns a pointer to b, for use in update structs.
+func Bool(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// ListUsers returns all users.
+func (c *Client) ListUsers() ([]*User, error) {
+	var out struct {
+		Users []*User `json:"users"`
+	}
+	err := c.do("GET", "/users", nil, http.StatusOK, &out)
+	return out.Users, err
+}
+
+// CreateUser creates a new user and returns its ID.
+func (c *Client) CreateUser(u *User) (uint32, error) {
+	var out created
+	err := c.do("POST", "/users", u, http.StatusCreated, &out)
+	return out.ID, err
+}
+
+// GetUser returns the user with the given ID.
+func (c *Client) GetUser(id uint32) (*User, error) {
+	var out struct {
+		User *User `json:"user"`
+	}
+	err := c.do("GET", fmt.Sprintf("/users/%d", id), nil, http.StatusOK, &out)
+	return out.User, err
+}
+
+// UpdateUser changes the given fields of a user.
+func (c *Client) UpdateUser(id uint32, u *UserUpdate) error {
+	return c.do("PUT", fmt.Sprintf("/users/%d", id), u, http.StatusNoContent, nil)
+}
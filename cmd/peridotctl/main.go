@@ -0,0 +1,213 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Command peridotctl lists, shows, creates, updates and deletes
+// objects in a peridot API, acting as any of the test users. It
+// can also reset the database and load a named fixture set, so
+// that the state a test expects can be set up and inspected.
+//
+// Usage:
+//
+//	peridotctl [flags] list <resource> [parent args...]
+//	peridotctl [flags] show <resource> <id>
+//	peridotctl [flags] create <resource> [parent args...] field=value...
+//	peridotctl [flags] update <resource> <id> field=value...
+//	peridotctl [flags] delete <resource> <id>
+//	peridotctl [flags] reset
+//	peridotctl [flags] fixture <name>
+//
+// Field values are taken as strings for string fields, and as JSON
+// for everything else, e.g.
+//
+//	peridotctl create jobs 4 agent_id=2 'priorjob_ids=[2]' 'config={"kv":{"a":"b"}}'
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/client"
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+var (
+	rootURL = flag.String("root", "http://localhost:3005", "root URL of the peridot API")
+	as      = flag.String("as", "admin", "test user to act as: none, admin, operator, commenter, viewer or disabled")
+	token   = flag.String("token", "", "JWT to send as the bearer token, instead of a test user's")
+	jsonOut = flag.Bool("json", false, "print results as JSON instead of a table")
+)
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+
+	err := run(flag.Args())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "peridotctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: peridotctl [flags] <command> [args...]\n\n")
+	fmt.Fprintf(os.Stderr, "commands:\n")
+	fmt.Fprintf(os.Stderr, "  list <resource> [parent args...]\n")
+	fmt.Fprintf(os.Stderr, "  show <resource> <id>\n")
+	fmt.Fprintf(os.Stderr, "  create <resource> [parent args...] field=value...\n")
+	fmt.Fprintf(os.Stderr, "  update <resource> <id> field=value...\n")
+	fmt.Fprintf(os.Stderr, "  delete <resource> <id>\n")
+	fmt.Fprintf(os.Stderr, "  reset\n")
+	fmt.Fprintf(os.Stderr, "  fixture <name>    (one of %s)\n\n", strings.Join(fixtures.Names(), ", "))
+	fmt.Fprintf(os.Stderr, "resources:\n")
+	for _, r := range resources {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", r.name, r.help)
+	}
+	fmt.Fprintf(os.Stderr, "\nflags:\n")
+	flag.PrintDefaults()
+}
+
+func run(args []string) error {
+	if len(args) < 1 {
+		usage()
+		return fmt.Errorf("no command given")
+	}
+
+	var id client.Identity
+	if *token != "" {
+		id = client.Token(*token)
+	} else {
+		id = utils.Identity(*as)
+	}
+	c := client.New(*rootURL, id)
+
+	cmd, args := args[0], args[1:]
+	switch cmd {
+	case "reset":
+		return c.ResetDB()
+	case "fixture":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: fixture <name>; available sets are %v", fixtures.Names())
+		}
+		return fixtures.Load(*rootURL, args[0])
+	case "list", "show", "create", "update", "delete":
+		// handled below
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
+	}
+
+	if len(args) < 1 {
+		return fmt.Errorf("usage: %s <resource> ...", cmd)
+	}
+	r, err := findResource(args[0])
+	if err != nil {
+		return err
+	}
+	pos, fields, err := splitArgs(args[1:])
+	if err != nil {
+		return err
+	}
+
+	switch cmd {
+	case "list":
+		if r.list == nil {
+			return fmt.Errorf("%s cannot be listed", r.name)
+		}
+		if len(fields) > 0 {
+			return fmt.Errorf("list does not take fields")
+		}
+		v, err := r.list(c, pos)
+		if err != nil {
+			return err
+		}
+		return output(v)
+
+	case "show":
+		if r.show == nil {
+			return fmt.Errorf("%s cannot be shown", r.name)
+		}
+		oid, err := oneID(pos, fields)
+		if err != nil {
+			return err
+		}
+		v, err := r.show(c, oid)
+		if err != nil {
+			return err
+		}
+		return output(v)
+
+	case "create":
+		if r.create == nil {
+			return fmt.Errorf("%s cannot be created", r.name)
+		}
+		newID, err := r.create(c, pos, fields)
+		if err != nil {
+			return err
+		}
+		if newID != 0 {
+			return output(map[string]uint32{"id": newID})
+		}
+		return nil
+
+	case "update":
+		if r.update == nil {
+			return fmt.Errorf("%s cannot be updated", r.name)
+		}
+		if len(pos) != 1 {
+			return fmt.Errorf("usage: update %s <id> field=value...", r.name)
+		}
+		oid, err := parseID(pos[0])
+		if err != nil {
+			return err
+		}
+		return r.update(c, oid, fields)
+
+	case "delete":
+		if r.delete == nil {
+			return fmt.Errorf("%s cannot be deleted", r.name)
+		}
+		oid, err := oneID(pos, fields)
+		if err != nil {
+			return err
+		}
+		return r.delete(c, oid)
+	}
+
+	return nil
+}
+
+// splitArgs separates field=value arguments from positional ones.
+func splitArgs(args []string) ([]string, map[string]string, error) {
+	pos := []string{}
+	fields := map[string]string{}
+	for _, a := range args {
+		i := strings.Index(a, "=")
+		if i < 0 {
+			if len(fields) > 0 {
+				return nil, nil, fmt.Errorf("positional argument %q after fields", a)
+			}
+			pos = append(pos, a)
+			continue
+		}
+		fields[a[:i]] = a[i+1:]
+	}
+	return pos, fields, nil
+}
+
+// oneID returns the single ID argument of a show or delete.
+func oneID(pos []string, fields map[string]string) (uint32, error) {
+	if len(pos) != 1 || len(fields) > 0 {
+		return 0, fmt.Errorf("expected a single ID argument")
+	}
+	return parseID(pos[0])
+}
+
+func parseID(s string) (uint32, error) {
+	n, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q", s)
+	}
+	return uint32(n), nil
+}
@@ -0,0 +1,116 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// output prints v, which is a struct, a pointer to one, or a slice
+// of either, as a table or as JSON depending on the -json flag.
+func output(v interface{}) error {
+	if *jsonOut {
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 8, 4, 1, ' ', 0)
+	defer w.Flush()
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return fmt.Errorf("no object in response")
+	}
+	switch rv.Kind() {
+	case reflect.Map:
+		// e.g. the ID of a created object
+		for _, k := range rv.MapKeys() {
+			fmt.Fprintf(w, "%v\t%v\n", k, rv.MapIndex(k))
+		}
+		return nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.String {
+			for i := 0; i < rv.Len(); i++ {
+				fmt.Fprintln(w, rv.Index(i))
+			}
+			return nil
+		}
+		fmt.Fprintln(w, strings.Join(headers(rv.Type().Elem()), "\t"))
+		for i := 0; i < rv.Len(); i++ {
+			fmt.Fprintln(w, strings.Join(row(rv.Index(i)), "\t"))
+		}
+		return nil
+	default:
+		// a single object is shown as one field per line
+		hs := headers(rv.Type())
+		vs := row(rv)
+		for i := range hs {
+			fmt.Fprintf(w, "%s\t%s\n", hs[i], vs[i])
+		}
+		return nil
+	}
+}
+
+// headers returns the JSON names of the fields of a struct type,
+// or of the struct type that t points to.
+func headers(t reflect.Type) []string {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	hs := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		hs = append(hs, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+	return hs
+}
+
+// row returns the fields of a struct value as strings, in the same
+// order as headers.
+func row(v reflect.Value) []string {
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	vs := []string{}
+	for i := 0; i < v.NumField(); i++ {
+		vs = append(vs, cell(v.Field(i).Interface()))
+	}
+	return vs
+}
+
+func cell(x interface{}) string {
+	switch y := x.(type) {
+	case string:
+		return y
+	case time.Time:
+		if y.IsZero() {
+			return ""
+		}
+		return y.Format(time.RFC3339)
+	case fmt.Stringer:
+		return y.String()
+	}
+
+	rv := reflect.ValueOf(x)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Struct:
+		if (rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice) && rv.IsNil() {
+			return ""
+		}
+		b, err := json.Marshal(x)
+		if err != nil {
+			return fmt.Sprint(x)
+		}
+		return string(b)
+	}
+	return fmt.Sprint(x)
+}
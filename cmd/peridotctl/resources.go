@@ -0,0 +1,389 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/client"
+)
+
+// resource describes the commands available for one type of
+// object. A nil function means that command is not supported.
+type resource struct {
+	name string
+	help string
+
+	// aliases are other names that the resource can be given by,
+	// e.g. the singular "branch" for "branches".
+	aliases []string
+
+	list   func(c *client.Client, pos []string) (interface{}, error)
+	show   func(c *client.Client, id uint32) (interface{}, error)
+	create func(c *client.Client, pos []string, fields map[string]string) (uint32, error)
+	update func(c *client.Client, id uint32, fields map[string]string) error
+	delete func(c *client.Client, id uint32) error
+}
+
+var resources = []*resource{
+	{
+		name:    "users",
+		aliases: []string{"user"},
+		help:    "list; show <id>; create; update <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			if err := wantArgs(pos, 0); err != nil {
+				return nil, err
+			}
+			return c.ListUsers()
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetUser(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			var u client.User
+			if err := wantArgs(pos, 0); err != nil {
+				return 0, err
+			}
+			if err := setFields(&u, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateUser(&u)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var u client.UserUpdate
+			if err := setFields(&u, fields); err != nil {
+				return err
+			}
+			return c.UpdateUser(id, &u)
+		},
+	},
+	{
+		name:    "projects",
+		aliases: []string{"project"},
+		help:    "list; show <id>; create; update <id>; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			if err := wantArgs(pos, 0); err != nil {
+				return nil, err
+			}
+			return c.ListProjects()
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetProject(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			var p client.Project
+			if err := wantArgs(pos, 0); err != nil {
+				return 0, err
+			}
+			if err := setFields(&p, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateProject(&p)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var p client.ProjectUpdate
+			if err := setFields(&p, fields); err != nil {
+				return err
+			}
+			return c.UpdateProject(id, &p)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteProject(id)
+		},
+	},
+	{
+		name:    "subprojects",
+		aliases: []string{"subproject"},
+		help:    "list [project-id]; show <id>; create; update <id>; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			ids, err := parseIDs(pos, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) == 1 {
+				return c.ListProjectSubprojects(ids[0])
+			}
+			return c.ListSubprojects()
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetSubproject(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			var s client.Subproject
+			if err := wantArgs(pos, 0); err != nil {
+				return 0, err
+			}
+			if err := setFields(&s, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateSubproject(&s)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var s client.SubprojectUpdate
+			if err := setFields(&s, fields); err != nil {
+				return err
+			}
+			return c.UpdateSubproject(id, &s)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteSubproject(id)
+		},
+	},
+	{
+		name:    "repos",
+		aliases: []string{"repo"},
+		help:    "list [subproject-id]; show <id>; create; update <id>; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			ids, err := parseIDs(pos, 0, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(ids) == 1 {
+				return c.ListSubprojectRepos(ids[0])
+			}
+			return c.ListRepos()
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetRepo(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			var r client.Repo
+			if err := wantArgs(pos, 0); err != nil {
+				return 0, err
+			}
+			if err := setFields(&r, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateRepo(&r)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var r client.RepoUpdate
+			if err := setFields(&r, fields); err != nil {
+				return err
+			}
+			return c.UpdateRepo(id, &r)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteRepo(id)
+		},
+	},
+	{
+		name:    "branches",
+		aliases: []string{"branch"},
+		help:    "list <repo-id>; create <repo-id> branch=<name>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			ids, err := parseIDs(pos, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListRepoBranches(ids[0])
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			ids, err := parseIDs(pos, 1, 1)
+			if err != nil {
+				return 0, err
+			}
+			var b struct {
+				Branch string `json:"branch"`
+			}
+			if err := setFields(&b, fields); err != nil {
+				return 0, err
+			}
+			return 0, c.CreateRepoBranch(ids[0], b.Branch)
+		},
+	},
+	{
+		name:    "repopulls",
+		aliases: []string{"repopull"},
+		help:    "list <repo-id> <branch>; show <id>; create <repo-id> <branch> [commit=...] [tag=...]; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			repoID, branch, err := repoBranch(pos)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListRepoPulls(repoID, branch)
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetRepoPull(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			repoID, branch, err := repoBranch(pos)
+			if err != nil {
+				return 0, err
+			}
+			var pr client.PullRequest
+			if err := setFields(&pr, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateRepoPull(repoID, branch, &pr)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteRepoPull(id)
+		},
+	},
+	{
+		name:    "agents",
+		aliases: []string{"agent"},
+		help:    "list; show <id>; create; update <id>; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			if err := wantArgs(pos, 0); err != nil {
+				return nil, err
+			}
+			return c.ListAgents()
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetAgent(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			var a client.Agent
+			if err := wantArgs(pos, 0); err != nil {
+				return 0, err
+			}
+			if err := setFields(&a, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateAgent(&a)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var a client.AgentUpdate
+			if err := setFields(&a, fields); err != nil {
+				return err
+			}
+			return c.UpdateAgent(id, &a)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteAgent(id)
+		},
+	},
+	{
+		name:    "jobs",
+		aliases: []string{"job"},
+		help:    "list <repopull-id>; show <id>; create <repopull-id>; update <id>; delete <id>",
+		list: func(c *client.Client, pos []string) (interface{}, error) {
+			ids, err := parseIDs(pos, 1, 1)
+			if err != nil {
+				return nil, err
+			}
+			return c.ListJobs(ids[0])
+		},
+		show: func(c *client.Client, id uint32) (interface{}, error) {
+			return c.GetJob(id)
+		},
+		create: func(c *client.Client, pos []string, fields map[string]string) (uint32, error) {
+			ids, err := parseIDs(pos, 1, 1)
+			if err != nil {
+				return 0, err
+			}
+			var j client.Job
+			if err := setFields(&j, fields); err != nil {
+				return 0, err
+			}
+			return c.CreateJob(ids[0], &j)
+		},
+		update: func(c *client.Client, id uint32, fields map[string]string) error {
+			var j client.JobUpdate
+			if err := setFields(&j, fields); err != nil {
+				return err
+			}
+			return c.UpdateJob(id, &j)
+		},
+		delete: func(c *client.Client, id uint32) error {
+			return c.DeleteJob(id)
+		},
+	},
+}
+
+func findResource(name string) (*resource, error) {
+	names := []string{}
+	for _, r := range resources {
+		if name == r.name {
+			return r, nil
+		}
+		for _, a := range r.aliases {
+			if name == a {
+				return r, nil
+			}
+		}
+		names = append(names, r.name)
+	}
+	return nil, fmt.Errorf("unknown resource %q; available resources are %s", name, strings.Join(names, ", "))
+}
+
+func wantArgs(pos []string, n int) error {
+	if len(pos) != n {
+		return fmt.Errorf("expected %d positional arguments, got %d", n, len(pos))
+	}
+	return nil
+}
+
+// parseIDs parses between min and max positional arguments as IDs.
+func parseIDs(pos []string, min int, max int) ([]uint32, error) {
+	if len(pos) < min || len(pos) > max {
+		return nil, fmt.Errorf("expected %d to %d ID arguments, got %d", min, max, len(pos))
+	}
+	ids := []uint32{}
+	for _, s := range pos {
+		id, err := parseID(s)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func repoBranch(pos []string) (uint32, string, error) {
+	if len(pos) != 2 {
+		return 0, "", fmt.Errorf("expected <repo-id> <branch>")
+	}
+	id, err := parseID(pos[0])
+	return id, pos[1], err
+}
+
+// setFields fills in the fields of the struct pointed to by v,
+// matching field names against its JSON tags. Values for string
+// fields are used as-is; all other values are parsed as JSON.
+func setFields(v interface{}, fields map[string]string) error {
+	t := reflect.TypeOf(v).Elem()
+	kinds := map[string]reflect.Kind{}
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		kinds[name] = f.Type.Kind()
+		names = append(names, name)
+	}
+
+	obj := map[string]json.RawMessage{}
+	for k, s := range fields {
+		kind, ok := kinds[k]
+		if !ok {
+			return fmt.Errorf("unknown field %q; available fields are %s", k, strings.Join(names, ", "))
+		}
+		if kind == reflect.String {
+			b, _ := json.Marshal(s)
+			obj[k] = b
+		} else {
+			if !json.Valid([]byte(s)) {
+				return fmt.Errorf("value for field %q is not valid JSON: %s", k, s)
+			}
+			obj[k] = json.RawMessage(s)
+		}
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return fmt.Errorf("invalid field values: %v", err)
+	}
+	err = json.Unmarshal(b, v)
+	if err != nil {
+		return fmt.Errorf("invalid field values: %v", err)
+	}
+	return nil
+}
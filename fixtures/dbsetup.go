@@ -4,10 +4,8 @@ package fixtures
 
 import (
 	"fmt"
-	"io/ioutil"
-	"net/http"
-	"strings"
 
+	"github.com/swinslow/peridot-api-testing/client"
 	"github.com/swinslow/peridot-api-testing/test/utils"
 )
 
@@ -15,32 +13,26 @@ import (
 // initial clean state. Only the initial github admin user
 // will be set.
 func ResetDB(root string) error {
-	resetCommand := `{"command": "resetDB"}`
-	client := &http.Client{}
-	req, err := http.NewRequest("POST", root+"/admin/db", strings.NewReader(resetCommand))
+	err := client.New(root, utils.Identity("admin")).ResetDB()
 	if err != nil {
-		return fmt.Errorf("got error from resetDB http request creator: %s", err)
+		return fmt.Errorf("resetDB command failed: %v", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
-	utils.AddAuthHeader(nil, "", req, "admin")
-
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
-	}
-
-	if resp.StatusCode != 204 {
-		b, err := ioutil.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err != nil {
-			return fmt.Errorf("expected 204, got %d from resetDB command: %s; with error reading response body: %v", resp.StatusCode, string(b), err)
-		}
-		return fmt.Errorf("expected 204, got %d from resetDB command: %s", resp.StatusCode, string(b))
-	}
-
 	return nil
 }
 
+// gitRepos, if set, holds local git repositories that the repos
+// and repopulls in the fixture should refer to, instead of the
+// made-up addresses and commits.
+var gitRepos *GitRepos
+
+// UseGitRepos sets the fixture to point its repos and repopulls at
+// the given local git repositories, so that the repopulls can
+// actually be pulled. Passing nil restores the made-up addresses
+// and commits.
+func UseGitRepos(g *GitRepos) {
+	gitRepos = g
+}
+
 // SetupFixture makes calls to the peridot API to create
 // objects in its database, so that it is in a useful
 // state for functional tests.
@@ -67,25 +59,20 @@ func SetupFixture(root string) error {
 }
 
 func createUsers(root string) error {
-	url := root + "/users"
+	c := client.New(root, utils.Identity("admin"))
 
 	// ID 1, name "Admin", github "admin", access "admin" is
 	// created by default on creation and each reset
 
-	calls := []struct {
-		name   string
-		github string
-		access string
-	}{
-		{"Operator User", "operator", "operator"},
-		{"Commenter User", "commenter", "commenter"},
-		{"Viewer User", "viewer", "viewer"},
-		{"Disabled User", "disabled", "disabled"},
+	users := []*client.User{
+		{Name: "Operator User", Github: "operator", Access: "operator"},
+		{Name: "Commenter User", Github: "commenter", Access: "commenter"},
+		{Name: "Viewer User", Github: "viewer", Access: "viewer"},
+		{Name: "Disabled User", Github: "disabled", Access: "disabled"},
 	}
 
-	for _, c := range calls {
-		body := fmt.Sprintf(`{"name": "%s", "github": "%s", "access": "%s"}`, c.name, c.github, c.access)
-		err := utils.PostNoRes(url, body, 201, "admin")
+	for _, u := range users {
+		_, err := c.CreateUser(u)
 		if err != nil {
 			return err
 		}
@@ -95,20 +82,16 @@ func createUsers(root string) error {
 }
 
 func createProjects(root string) error {
-	url := root + "/projects"
+	c := client.New(root, utils.Identity("operator"))
 
-	calls := []struct {
-		name     string
-		fullname string
-	}{
-		{"xyzzy", "The xyzzy Project"},
-		{"frotz", "The frotz Project"},
-		{"gnusto", "The gnusto Project"},
+	projects := []*client.Project{
+		{Name: "xyzzy", Fullname: "The xyzzy Project"},
+		{Name: "frotz", Fullname: "The frotz Project"},
+		{Name: "gnusto", Fullname: "The gnusto Project"},
 	}
 
-	for _, c := range calls {
-		body := fmt.Sprintf(`{"name": "%s", "fullname": "%s"}`, c.name, c.fullname)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for _, p := range projects {
+		_, err := c.CreateProject(p)
 		if err != nil {
 			return err
 		}
@@ -118,22 +101,17 @@ func createProjects(root string) error {
 }
 
 func createSubprojects(root string) error {
-	url := root + "/subprojects"
+	c := client.New(root, utils.Identity("operator"))
 
-	calls := []struct {
-		projectID uint32
-		name      string
-		fullname  string
-	}{
-		{2, "blorple", "The blorple Subproject"},
-		{2, "filfre", "The filfre Subproject"},
-		{2, "fweep", "The fweep Subproject"},
-		{3, "girgol", "The girgol Subproject"},
+	subprojects := []*client.Subproject{
+		{ProjectID: 2, Name: "blorple", Fullname: "The blorple Subproject"},
+		{ProjectID: 2, Name: "filfre", Fullname: "The filfre Subproject"},
+		{ProjectID: 2, Name: "fweep", Fullname: "The fweep Subproject"},
+		{ProjectID: 3, Name: "girgol", Fullname: "The girgol Subproject"},
 	}
 
-	for _, c := range calls {
-		body := fmt.Sprintf(`{"project_id": %d, "name": "%s", "fullname": "%s"}`, c.projectID, c.name, c.fullname)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for _, sp := range subprojects {
+		_, err := c.CreateSubproject(sp)
 		if err != nil {
 			return err
 		}
@@ -143,22 +121,20 @@ func createSubprojects(root string) error {
 }
 
 func createRepos(root string) error {
-	url := root + "/repos"
+	c := client.New(root, utils.Identity("operator"))
 
-	calls := []struct {
-		subprojectID uint32
-		name         string
-		address      string
-	}{
-		{2, "filfre-core", "https://example.com/filfre-core.git"},
-		{2, "filfre-api", "https://example.com/filfre-api.git"},
-		{1, "blorple-c", "https://example.com/blorple-c.git"},
-		{4, "girgol", "https://example.com/girgol.git"},
+	repos := []*client.Repo{
+		{SubprojectID: 2, Name: "filfre-core", Address: "https://example.com/filfre-core.git"},
+		{SubprojectID: 2, Name: "filfre-api", Address: "https://example.com/filfre-api.git"},
+		{SubprojectID: 1, Name: "blorple-c", Address: "https://example.com/blorple-c.git"},
+		{SubprojectID: 4, Name: "girgol", Address: "https://example.com/girgol.git"},
 	}
 
-	for _, c := range calls {
-		body := fmt.Sprintf(`{"subproject_id": %d, "name": "%s", "address": "%s"}`, c.subprojectID, c.name, c.address)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for i, r := range repos {
+		if gitRepos != nil {
+			r.Address = gitRepos.Repos[i].Address
+		}
+		_, err := c.CreateRepo(r)
 		if err != nil {
 			return err
 		}
@@ -168,6 +144,8 @@ func createRepos(root string) error {
 }
 
 func createRepoBranches(root string) error {
+	c := client.New(root, utils.Identity("operator"))
+
 	calls := []struct {
 		repoID uint32
 		branch string
@@ -180,10 +158,8 @@ func createRepoBranches(root string) error {
 		{1, "testing"},
 	}
 
-	for _, c := range calls {
-		url := fmt.Sprintf("%s/repos/%d/branches", root, c.repoID)
-		body := fmt.Sprintf(`{"branch": "%s"}`, c.branch)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for _, call := range calls {
+		err := c.CreateRepoBranch(call.repoID, call.branch)
 		if err != nil {
 			return err
 		}
@@ -193,23 +169,38 @@ func createRepoBranches(root string) error {
 }
 
 func createRepoPulls(root string) error {
+	c := client.New(root, utils.Identity("operator"))
+
 	calls := []struct {
 		repoID uint32
 		branch string
-		vType  string
-		v      string
+		commit string
 	}{
-		{1, "master", "commit", "22337864e74c9f54b1da4a64aaf7587ffa788039"},
-		{2, "dev-2.1", "commit", "7864e74c9f54b1da4a64aaf7587ffa7880392233"},
-		{2, "dev", "commit", "e74c9f54b1da4a64aaf7587ffa78803922337864"},
-		{2, "dev-2.1", "commit", "9f54b1da4a64aaf7587ffa78803922337864e74c"},
-		{1, "testing", "commit", "b1da4a64aaf7587ffa78803922337864e74c9f54"},
+		{1, "master", "22337864e74c9f54b1da4a64aaf7587ffa788039"},
+		{2, "dev-2.1", "7864e74c9f54b1da4a64aaf7587ffa7880392233"},
+		{2, "dev", "e74c9f54b1da4a64aaf7587ffa78803922337864"},
+		{2, "dev-2.1", "9f54b1da4a64aaf7587ffa78803922337864e74c"},
+		{1, "testing", "b1da4a64aaf7587ffa78803922337864e74c9f54"},
 	}
 
-	for _, c := range calls {
-		url := fmt.Sprintf("%s/repos/%d/branches/%s", root, c.repoID, c.branch)
-		body := fmt.Sprintf(`{"%s": "%s"}`, c.vType, c.v)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	if gitRepos != nil {
+		// use real commits instead: the pulls of each branch get
+		// that branch's most recent commits, oldest first
+		total := map[string]int{}
+		for _, call := range calls {
+			total[fmt.Sprintf("%d/%s", call.repoID, call.branch)]++
+		}
+		seen := map[string]int{}
+		for i, call := range calls {
+			key := fmt.Sprintf("%d/%s", call.repoID, call.branch)
+			commits := gitRepos.ByID(call.repoID).Branches[call.branch]
+			calls[i].commit = commits[len(commits)-total[key]+seen[key]]
+			seen[key]++
+		}
+	}
+
+	for _, call := range calls {
+		_, err := c.CreateRepoPull(call.repoID, call.branch, &client.PullRequest{Commit: call.commit})
 		if err != nil {
 			return err
 		}
@@ -219,27 +210,17 @@ func createRepoPulls(root string) error {
 }
 
 func createAgents(root string) error {
-	url := root + "/agents"
+	c := client.New(root, utils.Identity("operator"))
 
-	calls := []struct {
-		name         string
-		isActive     bool
-		address      string
-		port         int
-		isCodeReader bool
-		isSpdxReader bool
-		isCodeWriter bool
-		isSpdxWriter bool
-	}{
-		{"do-magic", true, "https://example.com/do-magic", 2087, false, true, false, false},
-		{"read-magic", true, "https://example.com/read-magic", 2088, true, true, false, true},
-		{"disabled", false, "localhost", 2057, false, true, false, false},
-		{"wevs", true, "localhost", 5010, true, true, true, false},
+	agents := []*client.Agent{
+		{Name: "do-magic", IsActive: true, Address: "https://example.com/do-magic", Port: 2087, IsSpdxReader: true},
+		{Name: "read-magic", IsActive: true, Address: "https://example.com/read-magic", Port: 2088, IsCodeReader: true, IsSpdxReader: true, IsSpdxWriter: true},
+		{Name: "disabled", IsActive: false, Address: "localhost", Port: 2057, IsSpdxReader: true},
+		{Name: "wevs", IsActive: true, Address: "localhost", Port: 5010, IsCodeReader: true, IsSpdxReader: true, IsCodeWriter: true},
 	}
 
-	for _, c := range calls {
-		body := fmt.Sprintf(`{"name":"%s", "is_active":%t, "address":"%s", "port":%d, "is_codereader":%t, "is_spdxreader":%t, "is_codewriter":%t, "is_spdxwriter":%t}`, c.name, c.isActive, c.address, c.port, c.isCodeReader, c.isSpdxReader, c.isCodeWriter, c.isSpdxWriter)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for _, a := range agents {
+		_, err := c.CreateAgent(a)
 		if err != nil {
 			return err
 		}
@@ -249,23 +230,28 @@ func createAgents(root string) error {
 }
 
 func createJobs(root string) error {
+	c := client.New(root, utils.Identity("operator"))
+
 	calls := []struct {
-		repopullID  uint32
-		agentID     uint32
-		priorJobIDs string
-		isReady     bool
-		config      string
+		repoPullID uint32
+		job        *client.Job
 	}{
-		{2, 1, "[]", true, `{"kv": {"hi":"steve"}}`},
-		{4, 1, "[]", true, `{}`},
-		{4, 2, "[2]", true, `{"codereader": {"primary": {"path": "/somewhere"}}}`},
-		{4, 4, "[2,3]", false, `{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}`},
+		{2, &client.Job{AgentID: 1, IsReady: true, Config: client.JobConfig{
+			KV: map[string]string{"hi": "steve"},
+		}}},
+		{4, &client.Job{AgentID: 1, IsReady: true}},
+		{4, &client.Job{AgentID: 2, PriorJobIDs: []uint32{2}, IsReady: true, Config: client.JobConfig{
+			CodeReader: map[string]client.JobPathConfig{"primary": {Path: "/somewhere"}},
+		}}},
+		{4, &client.Job{AgentID: 4, PriorJobIDs: []uint32{2, 3}, IsReady: false, Config: client.JobConfig{
+			KV:         map[string]string{"hello": "world"},
+			CodeReader: map[string]client.JobPathConfig{"godeps": {PriorJobID: 3}},
+			SpdxReader: map[string]client.JobPathConfig{"primary": {Path: "/path/wherever"}, "godeps": {PriorJobID: 3}},
+		}}},
 	}
 
-	for _, c := range calls {
-		url := fmt.Sprintf("%s/repopulls/%d/jobs", root, c.repopullID)
-		body := fmt.Sprintf(`{"agent_id": %d, "priorjob_ids": %s, "is_ready": %t, "config": %s}`, c.agentID, c.priorJobIDs, c.isReady, c.config)
-		err := utils.PostNoRes(url, body, 201, "operator")
+	for _, call := range calls {
+		_, err := c.CreateJob(call.repoPullID, call.job)
 		if err != nil {
 			return err
 		}
@@ -0,0 +1,183 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package fixtures
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/swinslow/peridot-api-testing/client"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// ScaleConfig describes a generated tree of objects: Projects
+// projects, each with SubprojectsPerProject subprojects, and so on
+// down to JobsPerPull jobs on each repopull.
+type ScaleConfig struct {
+	// Seed determines the generated names and commits, so that
+	// the same config always generates the same objects.
+	Seed int64
+
+	Projects              int
+	SubprojectsPerProject int
+	ReposPerSubproject    int
+	BranchesPerRepo       int
+	PullsPerBranch        int
+	JobsPerPull           int
+}
+
+// DefaultScaleConfig is the config for the "scale" fixture set.
+var DefaultScaleConfig = ScaleConfig{
+	Seed:                  1,
+	Projects:              100,
+	SubprojectsPerProject: 2,
+	ReposPerSubproject:    2,
+	BranchesPerRepo:       2,
+	PullsPerBranch:        2,
+	JobsPerPull:           2,
+}
+
+// Generated holds the objects that Generate created, with their
+// IDs filled in, in the order they were created.
+type Generated struct {
+	Projects    []*client.Project
+	Subprojects []*client.Subproject
+	Repos       []*client.Repo
+	Branches    map[uint32][]string
+	Pulls       []*client.RepoPull
+	Jobs        []*client.Job
+}
+
+// words are used to build the generated names.
+var words = []string{
+	"aimfiz", "bozbar", "cleesh", "frotz", "gnusto", "izyuk",
+	"kulcad", "nitfol", "ozmoo", "rezrov", "vaxum", "yomin",
+}
+
+// generator creates objects through the API as operator.
+type generator struct {
+	c   *client.Client
+	rng *rand.Rand
+	g   *Generated
+	n   int
+}
+
+// Generate creates the objects described by cfg, in addition to
+// whatever is already in the database. If cfg calls for any jobs,
+// a new agent is created for them to use.
+func Generate(root string, cfg ScaleConfig) (*Generated, error) {
+	gen := &generator{
+		c:   client.New(root, utils.Identity("operator")),
+		rng: rand.New(rand.NewSource(cfg.Seed)),
+		g:   &Generated{Branches: map[uint32][]string{}},
+	}
+
+	var agentID uint32
+	if cfg.JobsPerPull > 0 {
+		var err error
+		agentID, err = gen.c.CreateAgent(&client.Agent{
+			Name:         gen.name("agent"),
+			IsActive:     true,
+			Address:      "localhost",
+			Port:         2000 + gen.rng.Intn(1000),
+			IsSpdxReader: true,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("could not create agent: %v", err)
+		}
+	}
+
+	for i := 0; i < cfg.Projects; i++ {
+		p := &client.Project{Name: gen.name("p")}
+		p.Fullname = "The " + p.Name + " Project"
+		err := gen.create(&p.ID, func() (uint32, error) { return gen.c.CreateProject(p) })
+		if err != nil {
+			return nil, err
+		}
+		gen.g.Projects = append(gen.g.Projects, p)
+
+		for j := 0; j < cfg.SubprojectsPerProject; j++ {
+			sp := &client.Subproject{ProjectID: p.ID, Name: gen.name("sp")}
+			sp.Fullname = "The " + sp.Name + " Subproject"
+			err = gen.create(&sp.ID, func() (uint32, error) { return gen.c.CreateSubproject(sp) })
+			if err != nil {
+				return nil, err
+			}
+			gen.g.Subprojects = append(gen.g.Subprojects, sp)
+
+			for k := 0; k < cfg.ReposPerSubproject; k++ {
+				err = gen.repo(sp.ID, agentID, &cfg)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return gen.g, nil
+}
+
+// repo creates a repo in a subproject, with its branches, pulls
+// and jobs.
+func (gen *generator) repo(subprojectID uint32, agentID uint32, cfg *ScaleConfig) error {
+	r := &client.Repo{SubprojectID: subprojectID, Name: gen.name("r")}
+	r.Address = "https://example.com/" + r.Name + ".git"
+	err := gen.create(&r.ID, func() (uint32, error) { return gen.c.CreateRepo(r) })
+	if err != nil {
+		return err
+	}
+	gen.g.Repos = append(gen.g.Repos, r)
+
+	for b := 0; b < cfg.BranchesPerRepo; b++ {
+		branch := gen.name("b")
+		err = gen.c.CreateRepoBranch(r.ID, branch)
+		if err != nil {
+			return fmt.Errorf("could not create branch %s on repo %d: %v", branch, r.ID, err)
+		}
+		gen.g.Branches[r.ID] = append(gen.g.Branches[r.ID], branch)
+
+		for p := 0; p < cfg.PullsPerBranch; p++ {
+			pull := &client.RepoPull{RepoID: r.ID, Branch: branch, Commit: gen.commit()}
+			err = gen.create(&pull.ID, func() (uint32, error) {
+				return gen.c.CreateRepoPull(r.ID, branch, &client.PullRequest{Commit: pull.Commit})
+			})
+			if err != nil {
+				return err
+			}
+			gen.g.Pulls = append(gen.g.Pulls, pull)
+
+			for j := 0; j < cfg.JobsPerPull; j++ {
+				job := &client.Job{RepoPullID: pull.ID, AgentID: agentID}
+				err = gen.create(&job.ID, func() (uint32, error) { return gen.c.CreateJob(pull.ID, job) })
+				if err != nil {
+					return err
+				}
+				gen.g.Jobs = append(gen.g.Jobs, job)
+			}
+		}
+	}
+
+	return nil
+}
+
+// create calls f and stores the new ID in id.
+func (gen *generator) create(id *uint32, f func() (uint32, error)) error {
+	newID, err := f()
+	if err != nil {
+		return fmt.Errorf("could not generate object: %v", err)
+	}
+	*id = newID
+	return nil
+}
+
+// name returns a new name with the given prefix. The counter keeps
+// names unique whatever words are chosen.
+func (gen *generator) name(prefix string) string {
+	gen.n++
+	return fmt.Sprintf("%s-%s-%s-%d", prefix, words[gen.rng.Intn(len(words))], words[gen.rng.Intn(len(words))], gen.n)
+}
+
+// commit returns a new made-up commit hash.
+func (gen *generator) commit() string {
+	return fmt.Sprintf("%016x%016x%08x", gen.rng.Uint64(), gen.rng.Uint64(), gen.rng.Uint32())
+}
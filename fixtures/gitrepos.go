@@ -0,0 +1,343 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package fixtures
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitRepo describes a local bare git repository created by
+// CreateGitRepos, and the known commits within it.
+type GitRepo struct {
+	// Name is the repo's name, matching its name in createRepos.
+	Name string
+
+	// Path is the path to the bare repository on disk.
+	Path string
+
+	// Address is the address that the API should use to pull
+	// from this repository.
+	Address string
+
+	// Branches maps each branch name to its commits, oldest first.
+	// The last commit is the branch's head.
+	Branches map[string][]string
+
+	// Tags maps each tag name to the commit it points to.
+	Tags map[string]string
+
+	// Files maps each commit to the contents of every file in
+	// the tree at that commit, keyed by path.
+	Files map[string]map[string]string
+}
+
+// Head returns the head commit of the given branch, or "" if the
+// branch does not exist.
+func (r *GitRepo) Head(branch string) string {
+	commits := r.Branches[branch]
+	if len(commits) == 0 {
+		return ""
+	}
+	return commits[len(commits)-1]
+}
+
+// GitRepos is the set of local git repositories that stand in for
+// the remote repositories in the default fixture.
+type GitRepos struct {
+	// Dir is the directory holding the bare repositories.
+	Dir string
+
+	// Repos holds the repositories in the same order, and so with
+	// the same IDs (counting from 1), as createRepos.
+	Repos []*GitRepo
+
+	daemon *exec.Cmd
+}
+
+// ByID returns the repository with the given fixture repo ID, or
+// nil if there is no such repository.
+func (g *GitRepos) ByID(id uint32) *GitRepo {
+	if id < 1 || int(id) > len(g.Repos) {
+		return nil
+	}
+	return g.Repos[id-1]
+}
+
+// gitCommitPlan describes one commit to make while generating a
+// fixture repository.
+type gitCommitPlan struct {
+	// branch is the branch to commit on. A branch that does not
+	// exist yet is created from the current head of master.
+	branch string
+
+	// message is the commit message.
+	message string
+
+	// files are written (or overwritten) before committing.
+	files map[string]string
+
+	// tag, if not empty, is a tag to create for this commit.
+	tag string
+}
+
+// gitRepoPlan describes a fixture repository to generate.
+type gitRepoPlan struct {
+	name    string
+	commits []gitCommitPlan
+}
+
+// gitRepoPlans are in the same order as the repos in createRepos,
+// and have (at least) the branches used by createRepoBranches and
+// createRepoPulls. Branches used by two pulls have at least two
+// commits of their own.
+var gitRepoPlans = []gitRepoPlan{
+	{"filfre-core", []gitCommitPlan{
+		{"master", "initial commit", map[string]string{
+			"README.md":  "# filfre-core\n",
+			"src/core.c": "// SPDX-License-Identifier: Apache-2.0\nint core(void) { return 0; }\n",
+		}, "v1.0"},
+		{"master", "add helper", map[string]string{
+			"src/helper.c": "// SPDX-License-Identifier: MIT\nint helper(void) { return 1; }\n",
+		}, ""},
+		{"testing", "add tests", map[string]string{
+			"test/core_test.c": "// SPDX-License-Identifier: Apache-2.0\nint main(void) { return core(); }\n",
+		}, "testing-1"},
+	}},
+	{"filfre-api", []gitCommitPlan{
+		{"master", "initial commit", map[string]string{
+			"README.md": "# filfre-api\n",
+			"api.go":    "// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later\npackage api\n",
+		}, "v1.0"},
+		{"dev", "start dev", map[string]string{
+			"dev.go": "// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later\npackage api\n\nconst Dev = true\n",
+		}, ""},
+		{"dev-2.1", "start 2.1", map[string]string{
+			"version.go": "// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later\npackage api\n\nconst Version = \"2.1.0\"\n",
+		}, "v2.1.0"},
+		{"dev-2.1", "bump to 2.1.1", map[string]string{
+			"version.go": "// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later\npackage api\n\nconst Version = \"2.1.1\"\n",
+		}, "v2.1.1"},
+		{"master", "update readme", map[string]string{
+			"README.md": "# filfre-api\n\nThe filfre API.\n",
+		}, ""},
+	}},
+	{"blorple-c", []gitCommitPlan{
+		{"master", "initial commit", map[string]string{
+			"blorple.c": "/* SPDX-License-Identifier: BSD-3-Clause */\nint blorple(void) { return 2; }\n",
+			"COPYING":   "BSD-3-Clause\n",
+		}, "v0.1"},
+	}},
+	{"girgol", []gitCommitPlan{
+		{"master", "initial commit", map[string]string{
+			"girgol.py": "# SPDX-License-Identifier: GPL-2.0-only\nprint('girgol')\n",
+		}, "v1.0"},
+		{"master", "add notice", map[string]string{
+			"NOTICE": "girgol has no license header in this file\n",
+		}, ""},
+	}},
+}
+
+// gitEnv is the environment for generating fixture commits. The
+// fixed identities and dates make the commit hashes the same on
+// every run.
+var gitEnv = []string{
+	"GIT_AUTHOR_NAME=Fixture Author",
+	"GIT_AUTHOR_EMAIL=fixture@example.com",
+	"GIT_AUTHOR_DATE=2019-06-01T12:00:00Z",
+	"GIT_COMMITTER_NAME=Fixture Author",
+	"GIT_COMMITTER_EMAIL=fixture@example.com",
+	"GIT_COMMITTER_DATE=2019-06-01T12:00:00Z",
+	"GIT_CONFIG_NOSYSTEM=1",
+	"HOME=/nonexistent",
+}
+
+// CreateGitRepos generates the fixture repositories as bare git
+// repositories in dir, replacing any that are already there.
+// baseAddress is the address under which the API can reach dir,
+// e.g. "git://test:9418" when served with ServeGit, or
+// "file:///srv/git" if dir is shared with the API's host.
+func CreateGitRepos(dir string, baseAddress string) (*GitRepos, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &GitRepos{Dir: dir}
+	for _, plan := range gitRepoPlans {
+		r, err := createGitRepo(dir, strings.TrimSuffix(baseAddress, "/"), plan)
+		if err != nil {
+			return nil, fmt.Errorf("error creating git fixture %s: %v", plan.name, err)
+		}
+		g.Repos = append(g.Repos, r)
+	}
+
+	return g, nil
+}
+
+// createGitRepo builds one repository in a scratch work tree, and
+// then clones it as a bare repository into dir.
+func createGitRepo(dir string, baseAddress string, plan gitRepoPlan) (*GitRepo, error) {
+	work, err := ioutil.TempDir("", "peridot-git-"+plan.name)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(work)
+
+	r := &GitRepo{
+		Name:     plan.name,
+		Path:     filepath.Join(dir, plan.name+".git"),
+		Address:  baseAddress + "/" + plan.name + ".git",
+		Branches: map[string][]string{},
+		Tags:     map[string]string{},
+		Files:    map[string]map[string]string{},
+	}
+
+	_, err = runGit(work, "init", "-q")
+	if err != nil {
+		return nil, err
+	}
+	// make sure the first branch is master, whatever the local
+	// default branch name is
+	_, err = runGit(work, "symbolic-ref", "HEAD", "refs/heads/master")
+	if err != nil {
+		return nil, err
+	}
+
+	tree := map[string]string{}
+	current := "master"
+	for _, c := range plan.commits {
+		if c.branch != current {
+			if _, ok := r.Branches[c.branch]; ok {
+				_, err = runGit(work, "checkout", "-q", c.branch)
+			} else {
+				_, err = runGit(work, "checkout", "-q", "-b", c.branch, "master")
+			}
+			if err != nil {
+				return nil, err
+			}
+			current = c.branch
+			if _, ok := r.Branches[c.branch]; ok {
+				tree = copyTree(r.Files[r.Head(c.branch)])
+			} else {
+				tree = copyTree(r.Files[r.Head("master")])
+			}
+		}
+
+		for path, content := range c.files {
+			full := filepath.Join(work, filepath.FromSlash(path))
+			err = os.MkdirAll(filepath.Dir(full), 0755)
+			if err != nil {
+				return nil, err
+			}
+			err = ioutil.WriteFile(full, []byte(content), 0644)
+			if err != nil {
+				return nil, err
+			}
+			tree[path] = content
+		}
+
+		_, err = runGit(work, "add", "-A")
+		if err != nil {
+			return nil, err
+		}
+		_, err = runGit(work, "commit", "-q", "-m", c.message)
+		if err != nil {
+			return nil, err
+		}
+		commit, err := runGit(work, "rev-parse", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := r.Branches[c.branch]; !ok && c.branch != "master" {
+			// a new branch starts with master's history
+			r.Branches[c.branch] = append([]string{}, r.Branches["master"]...)
+		}
+		r.Branches[c.branch] = append(r.Branches[c.branch], commit)
+		r.Files[commit] = copyTree(tree)
+
+		if c.tag != "" {
+			_, err = runGit(work, "tag", c.tag, commit)
+			if err != nil {
+				return nil, err
+			}
+			r.Tags[c.tag] = commit
+		}
+	}
+
+	err = os.RemoveAll(r.Path)
+	if err != nil {
+		return nil, err
+	}
+	_, err = runGit(dir, "clone", "-q", "--bare", work, r.Path)
+	if err != nil {
+		return nil, err
+	}
+	// allow git daemon to serve this repository
+	err = ioutil.WriteFile(filepath.Join(r.Path, "git-daemon-export-ok"), nil, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// copyTree returns a copy of the given map of file contents.
+func copyTree(tree map[string]string) map[string]string {
+	t := map[string]string{}
+	for k, v := range tree {
+		t[k] = v
+	}
+	return t
+}
+
+// runGit runs a git command in dir with the fixture environment,
+// and returns its trimmed standard output.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), gitEnv...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %v: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// ServeGit starts git daemon to serve the repositories on the
+// given port, so that they can be reached at git://host:port/.
+// The daemon runs until StopServing is called.
+func (g *GitRepos) ServeGit(port int) error {
+	cmd := exec.Command("git", "daemon", "--reuseaddr", "--export-all",
+		fmt.Sprintf("--port=%d", port),
+		"--base-path="+g.Dir, g.Dir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	err := cmd.Start()
+	if err != nil {
+		return fmt.Errorf("could not start git daemon: %v", err)
+	}
+
+	g.daemon = cmd
+	return nil
+}
+
+// StopServing stops the git daemon, if it is running.
+func (g *GitRepos) StopServing() error {
+	if g.daemon == nil {
+		return nil
+	}
+	err := g.daemon.Process.Kill()
+	g.daemon.Wait()
+	g.daemon = nil
+	return err
+}
@@ -0,0 +1,165 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package fixtures
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// snapshotSchema is the schema that holds the copies of the
+// fixture tables.
+const snapshotSchema = "harness_snapshot"
+
+// pgSnapshot is a copy of every table outside the system schemas,
+// taken within the database, plus the values of the sequences.
+type pgSnapshot struct {
+	db        *sql.DB
+	tables    []pgName
+	sequences map[pgName]pgSequence
+}
+
+// pgName is a schema-qualified table or sequence name.
+type pgName struct {
+	schema string
+	name   string
+}
+
+// quoted returns the name quoted for use in SQL.
+func (n pgName) quoted() string {
+	return pq.QuoteIdentifier(n.schema) + "." + pq.QuoteIdentifier(n.name)
+}
+
+// copy returns the quoted name of the table's copy.
+func (n pgName) copy() string {
+	return snapshotSchema + "." + pq.QuoteIdentifier(n.schema+"."+n.name)
+}
+
+// userSchemas restricts a query to schemas other than the system
+// ones and the snapshot's own.
+const userSchemas = `NOT IN ('pg_catalog', 'information_schema', '` + snapshotSchema + `')`
+
+type pgSequence struct {
+	lastValue int64
+	isCalled  bool
+}
+
+// newPGSnapshot connects to the database and copies its current
+// contents aside.
+func newPGSnapshot(dsn string) (*pgSnapshot, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	s := &pgSnapshot{db: db, sequences: map[pgName]pgSequence{}}
+	err = s.take()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *pgSnapshot) take() error {
+	rows, err := s.db.Query(`SELECT schemaname, tablename FROM pg_tables WHERE schemaname ` + userSchemas + ` ORDER BY schemaname, tablename`)
+	if err != nil {
+		return err
+	}
+	s.tables, err = scanNames(rows)
+	if err != nil {
+		return err
+	}
+	if len(s.tables) == 0 {
+		return fmt.Errorf("no tables found in the database")
+	}
+
+	rows, err = s.db.Query(`SELECT sequence_schema, sequence_name FROM information_schema.sequences WHERE sequence_schema ` + userSchemas)
+	if err != nil {
+		return err
+	}
+	seqs, err := scanNames(rows)
+	if err != nil {
+		return err
+	}
+	for _, seq := range seqs {
+		var v pgSequence
+		err = s.db.QueryRow(`SELECT last_value, is_called FROM `+seq.quoted()).Scan(&v.lastValue, &v.isCalled)
+		if err != nil {
+			return err
+		}
+		s.sequences[seq] = v
+	}
+
+	stmts := []string{
+		`DROP SCHEMA IF EXISTS ` + snapshotSchema + ` CASCADE`,
+		`CREATE SCHEMA ` + snapshotSchema,
+	}
+	for _, t := range s.tables {
+		stmts = append(stmts, fmt.Sprintf(`CREATE TABLE %s AS SELECT * FROM %s`, t.copy(), t.quoted()))
+	}
+	return s.exec(stmts)
+}
+
+// restore replaces the contents of every table with its copy, and
+// resets the sequences. Foreign key triggers are disabled for the
+// transaction, so that the tables can be filled in any order.
+func (s *pgSnapshot) restore() error {
+	quoted := []string{}
+	for _, t := range s.tables {
+		quoted = append(quoted, t.quoted())
+	}
+
+	stmts := []string{
+		`SET LOCAL session_replication_role = replica`,
+		`TRUNCATE ` + strings.Join(quoted, ", ") + ` RESTART IDENTITY CASCADE`,
+	}
+	for _, t := range s.tables {
+		stmts = append(stmts, fmt.Sprintf(`INSERT INTO %s SELECT * FROM %s`, t.quoted(), t.copy()))
+	}
+	for seq, v := range s.sequences {
+		stmts = append(stmts, fmt.Sprintf(`SELECT setval(%s, %d, %t)`, pq.QuoteLiteral(seq.quoted()), v.lastValue, v.isCalled))
+	}
+	return s.exec(stmts)
+}
+
+// exec runs the statements in a single transaction.
+func (s *pgSnapshot) exec(stmts []string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	for _, stmt := range stmts {
+		_, err = tx.Exec(stmt)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("%s: %v", stmt, err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *pgSnapshot) close() error {
+	_, err := s.db.Exec(`DROP SCHEMA IF EXISTS ` + snapshotSchema + ` CASCADE`)
+	cerr := s.db.Close()
+	if err != nil {
+		return err
+	}
+	return cerr
+}
+
+func scanNames(rows *sql.Rows) ([]pgName, error) {
+	defer rows.Close()
+	ns := []pgName{}
+	for rows.Next() {
+		var n pgName
+		err := rows.Scan(&n.schema, &n.name)
+		if err != nil {
+			return nil, err
+		}
+		ns = append(ns, n)
+	}
+	return ns, rows.Err()
+}
@@ -0,0 +1,49 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package fixtures
+
+import (
+	"fmt"
+	"sort"
+)
+
+// sets maps the name of each fixture set to a function that
+// creates it in a freshly-reset database.
+var sets = map[string]func(root string) error{
+	"empty":   func(root string) error { return nil },
+	"default": SetupFixture,
+	"scale": func(root string) error {
+		err := SetupFixture(root)
+		if err != nil {
+			return err
+		}
+		_, err = Generate(root, DefaultScaleConfig)
+		return err
+	},
+}
+
+// Names returns the names of the available fixture sets, sorted.
+func Names() []string {
+	names := make([]string, 0, len(sets))
+	for name := range sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Load resets the database and then creates the named fixture
+// set in it.
+func Load(root string, name string) error {
+	f, ok := sets[name]
+	if !ok {
+		return fmt.Errorf("unknown fixture set %q; available sets are %v", name, Names())
+	}
+
+	err := ResetDB(root)
+	if err != nil {
+		return err
+	}
+
+	return f(root)
+}
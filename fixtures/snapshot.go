@@ -0,0 +1,207 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package fixtures
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/client"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Restore modes, in the order that "auto" tries them.
+const (
+	// ModeAdmin uses the API's snapshotDB and restoreDB admin
+	// commands, if it has them.
+	ModeAdmin = "admin"
+
+	// ModePostgres copies the fixture tables aside in the
+	// database itself, and copies them back to restore.
+	ModePostgres = "postgres"
+
+	// ModeReplay resets the database and replays every fixture
+	// call, as the harness always used to.
+	ModeReplay = "replay"
+
+	// ModeAuto picks the first of the above that works.
+	ModeAuto = "auto"
+)
+
+// Restorer puts the database back into the fixture state before
+// each test.
+type Restorer struct {
+	root  string
+	mode  string
+	pg    *pgSnapshot
+	setup func(root string) error
+
+	// ReplayTime is how long the initial reset and replay took.
+	ReplayTime time.Duration
+
+	// Restores and RestoreTime count the calls to Restore and the
+	// total time they took.
+	Restores    int
+	RestoreTime time.Duration
+}
+
+// NewRestorer resets the database and sets up the fixture once, by
+// replay, and then takes a snapshot of it using the given mode. If
+// mode is ModeAuto, the first mode that works is used; a mode only
+// works if restoring with it undoes a change. dsn is the
+// Postgres connection string for ModePostgres; if it is empty, that
+// mode is unavailable.
+func NewRestorer(root string, mode string, dsn string) (*Restorer, error) {
+	r := &Restorer{root: root, setup: SetupFixture}
+
+	start := time.Now()
+	err := r.replay()
+	if err != nil {
+		return nil, err
+	}
+	r.ReplayTime = time.Since(start)
+
+	var errs []error
+	for _, m := range []string{ModeAdmin, ModePostgres, ModeReplay} {
+		if mode != ModeAuto && mode != m {
+			continue
+		}
+		err = r.snapshot(m, dsn)
+		if err == nil && m != ModeReplay {
+			r.mode = m
+			err = r.verify()
+			if err != nil {
+				r.Close()
+				r.pg = nil
+				// the change made to check the restore may
+				// still be there, so set up the fixture again
+				// before trying the next mode
+				rerr := r.replay()
+				if rerr != nil {
+					return nil, rerr
+				}
+			}
+		}
+		if err == nil {
+			r.mode = m
+			return r, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %v", m, err))
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("unknown restore mode %q", mode)
+	}
+	return nil, fmt.Errorf("could not snapshot the fixture: %v", errs)
+}
+
+// snapshot takes a snapshot of the current database state using
+// the given mode.
+func (r *Restorer) snapshot(mode string, dsn string) error {
+	switch mode {
+	case ModeAdmin:
+		return adminCommand(r.root, "snapshotDB")
+	case ModePostgres:
+		if dsn == "" {
+			return fmt.Errorf("no database connection string given")
+		}
+		pg, err := newPGSnapshot(dsn)
+		if err != nil {
+			return err
+		}
+		r.pg = pg
+		return nil
+	case ModeReplay:
+		return nil
+	}
+	return fmt.Errorf("unknown restore mode %q", mode)
+}
+
+// verify checks that restoring really puts the database back into
+// the snapshot state, by creating a project, restoring, and checking
+// that the projects are the same as before. Otherwise an API that
+// accepts the snapshot and restore commands but ignores them would
+// leave every test after the first running on a dirty database.
+func (r *Restorer) verify() error {
+	c := client.New(r.root, utils.Identity("admin"))
+	before, err := c.ListProjects()
+	if err != nil {
+		return err
+	}
+	_, err = c.CreateProject(&client.Project{Name: "restorecheck", Fullname: "Restore check"})
+	if err != nil {
+		return fmt.Errorf("could not change the database to check restoring: %v", err)
+	}
+	changed, err := c.ListProjects()
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(before, changed) {
+		return fmt.Errorf("could not check restoring: the new project is not listed")
+	}
+	err = r.restore()
+	if err != nil {
+		return err
+	}
+	after, err := c.ListProjects()
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(before, after) {
+		return fmt.Errorf("restoring did not undo a change: %d projects in the snapshot, %d after restoring", len(before), len(after))
+	}
+	return nil
+}
+
+// Mode returns the restore mode in use.
+func (r *Restorer) Mode() string {
+	return r.mode
+}
+
+// Restore puts the database back into the fixture state.
+func (r *Restorer) Restore() error {
+	start := time.Now()
+	err := r.restore()
+	r.Restores++
+	r.RestoreTime += time.Since(start)
+	return err
+}
+
+func (r *Restorer) restore() error {
+	switch r.mode {
+	case ModeAdmin:
+		return adminCommand(r.root, "restoreDB")
+	case ModePostgres:
+		return r.pg.restore()
+	default:
+		return r.replay()
+	}
+}
+
+// Saved estimates how much time the restores saved compared to
+// replaying the fixture each time.
+func (r *Restorer) Saved() time.Duration {
+	return time.Duration(r.Restores)*r.ReplayTime - r.RestoreTime
+}
+
+// Close releases the snapshot's resources.
+func (r *Restorer) Close() error {
+	if r.pg != nil {
+		return r.pg.close()
+	}
+	return nil
+}
+
+func (r *Restorer) replay() error {
+	err := ResetDB(r.root)
+	if err != nil {
+		return err
+	}
+	return r.setup(r.root)
+}
+
+// adminCommand sends a command to the API's /admin/db endpoint.
+func adminCommand(root string, command string) error {
+	c := client.New(root, utils.Identity("admin"))
+	return c.AdminDB(command)
+}
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// intList is a flag value holding a comma-separated list of
+// positive integers.
+type intList []int
+
+func (l *intList) String() string {
+	if l == nil {
+		return ""
+	}
+	ss := []string{}
+	for _, n := range *l {
+		ss = append(ss, strconv.Itoa(n))
+	}
+	return strings.Join(ss, ",")
+}
+
+func (l *intList) Set(s string) error {
+	ns := []int{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		n, err := strconv.Atoi(f)
+		if err != nil || n <= 0 {
+			return fmt.Errorf("%q is not a positive integer", f)
+		}
+		ns = append(ns, n)
+	}
+	*l = ns
+	return nil
+}
+
+// stringList is a flag value holding a comma-separated list of
+// strings.
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	ss := []string{}
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			ss = append(ss, f)
+		}
+	}
+	*l = ss
+	return nil
+}
+
+// shuffleFlag is a flag value that can be given on its own, to
+// shuffle with a seed based on the time, or with a seed, as in
+// -shuffle=42 or -shuffle 42, to reproduce an earlier order.
+type shuffleFlag struct {
+	on   bool
+	seed int64
+}
+
+func (f *shuffleFlag) IsBoolFlag() bool {
+	return true
+}
+
+func (f *shuffleFlag) String() string {
+	if f == nil || !f.on {
+		return "false"
+	}
+	return strconv.FormatInt(f.seed, 10)
+}
+
+func (f *shuffleFlag) Set(s string) error {
+	switch s {
+	case "true":
+		f.on = true
+		f.seed = time.Now().UnixNano()
+	case "false":
+		f.on = false
+	default:
+		seed, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not true, false or a seed", s)
+		}
+		f.on = true
+		f.seed = seed
+	}
+	return nil
+}
+
+// takeSeed handles a seed given as the argument after -shuffle, as
+// in -shuffle 42. Since -shuffle can be given on its own, the flag
+// package leaves the seed, and everything after it, as positional
+// arguments. If the first of fs's positional arguments directly
+// follows -shuffle in args and is a number, it is taken as the seed,
+// and the arguments after it are parsed as flags.
+func (f *shuffleFlag) takeSeed(fs *flag.FlagSet, args []string) error {
+	for {
+		rest := fs.Args()
+		i := len(args) - len(rest)
+		if len(rest) == 0 || i == 0 || (args[i-1] != "-shuffle" && args[i-1] != "--shuffle") {
+			return nil
+		}
+		seed, err := strconv.ParseInt(rest[0], 10, 64)
+		if err != nil {
+			return nil
+		}
+		f.on = true
+		f.seed = seed
+
+		args = rest[1:]
+		err = fs.Parse(args)
+		if err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+)
+
+// setupGitRepos creates the local git fixture repositories, starts
+// serving them if requested, and points the fixture at them. The
+// caller must call StopServing on the result when done.
+func setupGitRepos() (*fixtures.GitRepos, error) {
+	g, err := fixtures.CreateGitRepos(*gitDir, *gitBase)
+	if err != nil {
+		return nil, err
+	}
+
+	if *gitDaemonPort != 0 {
+		err = g.ServeGit(*gitDaemonPort)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	fmt.Printf("Using local git repositories in %s, at %s\n", g.Dir, *gitBase)
+	fixtures.UseGitRepos(g)
+	return g, nil
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// updateGoldenFiles lists the golden files that differ from the
+// responses seen during the run, asks for confirmation on in, and
+// if given, rewrites them and prints a summary of what changed. It
+// returns false if there were updates that were not written.
+func updateGoldenFiles(in io.Reader) (bool, error) {
+	us := utils.GoldenUpdates()
+	if len(us) == 0 {
+		fmt.Printf("\nAll golden files match the responses; nothing to update.\n")
+		return true, nil
+	}
+
+	fmt.Printf("\n%d golden files differ from the responses:\n", len(us))
+	for _, u := range us {
+		fmt.Printf("  %s %s\n", goldenChange(u), u.File)
+	}
+
+	fmt.Printf("\nUpdate %d golden files? [y/N] ", len(us))
+	answer, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return false, err
+	}
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	if answer != "y" && answer != "yes" {
+		fmt.Printf("Golden files not updated; %d still differ from the responses.\n", len(us))
+		return false, nil
+	}
+
+	err = utils.WriteGoldenUpdates(us)
+	if err != nil {
+		return false, err
+	}
+
+	created, changed := 0, 0
+	fmt.Printf("\nGolden files updated:\n")
+	for _, u := range us {
+		if u.Old == nil {
+			created++
+		} else {
+			changed++
+		}
+		fmt.Printf("  %s %s\n", goldenChange(u), u.File)
+	}
+	fmt.Printf("%d created, %d changed\n", created, changed)
+	return true, nil
+}
+
+// goldenChange describes how an update changes its golden file.
+func goldenChange(u *utils.GoldenUpdate) string {
+	if u.Old == nil {
+		return "new:    "
+	}
+	return "changed:"
+}
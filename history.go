@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/internal/history"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// sutVersion returns the version of the API under test: the
+// -sut-version flag if it is set, or else what is fetched from
+// -sut-version-path. It returns "" if neither is available.
+func sutVersion(root string) string {
+	if *sutVersionFlag != "" {
+		return *sutVersionFlag
+	}
+	if *sutVersionPath == "" {
+		return ""
+	}
+
+	code, b, err := utils.Send(nil, "", "GET", root+*sutVersionPath, "", "admin")
+	if err != nil || code != 200 {
+		fmt.Printf("Could not get the API's version from %s; recording it as unknown\n", *sutVersionPath)
+		return ""
+	}
+
+	var v struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(b, &v) == nil && v.Version != "" {
+		return v.Version
+	}
+	s := strings.TrimSpace(string(b))
+	if len(s) > 100 {
+		s = s[:100]
+	}
+	return s
+}
+
+// saveHistory saves the results of this run to the history store.
+// skipped are the tests that were skipped without being run, and
+// runs are the runs of the other tests.
+func saveHistory(start time.Time, root string, version string, skipped []*testresult.TestResult, runs [][]*testRun) error {
+	run := history.NewRun(start, version, root)
+	if *runID != "" {
+		run.ID = *runID
+	}
+
+	for _, r := range skipped {
+		run.Add(r, nil)
+	}
+	for _, trs := range runs {
+		rs := []*testresult.TestResult{}
+		for _, tr := range trs {
+			rs = append(rs, tr.res)
+		}
+		run.Add(summarizeRuns([][]*testRun{trs})[0], rs)
+	}
+
+	store, err := history.Open(*historyDir)
+	if err != nil {
+		return err
+	}
+	err = store.Save(run)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nSaved results as run %s in %s\n", run.ID, *historyDir)
+	return nil
+}
+
+// runReport prints a report on the runs in the history store, or
+// if a test is given as "suite:element:id", that test's history.
+// It returns the process exit code.
+func runReport(args []string) int {
+	if *historyDir == "" {
+		fmt.Printf("Usage: %s -history-dir DIR report [suite:element:id]\n", os.Args[0])
+		return 2
+	}
+
+	store, err := history.Open(*historyDir)
+	if err != nil {
+		fmt.Printf("Error opening history: %v\n", err)
+		return 1
+	}
+	runs, err := store.Load()
+	if err != nil {
+		fmt.Printf("Error reading history: %v\n", err)
+		return 1
+	}
+
+	if len(args) > 0 {
+		history.TestReport(os.Stdout, runs, strings.Join(args, " "))
+		return 0
+	}
+
+	history.Report(os.Stdout, runs, &history.ReportConfig{
+		Window:        *reportWindow,
+		MaxRows:       *reportRows,
+		SlowdownRatio: *reportSlowdown,
+		MinSlowdown:   *reportMinSlowdown,
+	})
+	return 0
+}
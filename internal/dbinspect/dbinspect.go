@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package dbinspect reads the API's Postgres database directly, so
+// that tests can check that the rows behind an API call are what
+// the API says they are.
+package dbinspect
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Inspector is a read-only view of the API's database.
+type Inspector struct {
+	db *sql.DB
+
+	// tables maps each unqualified table name to its quoted,
+	// schema-qualified name.
+	tables map[string]string
+}
+
+// Open connects to the database and finds its tables.
+func Open(dsn string) (*Inspector, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+	ins := &Inspector{db: db, tables: map[string]string{}}
+	err = ins.loadTables()
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return ins, nil
+}
+
+// Close closes the connection to the database.
+func (ins *Inspector) Close() error {
+	return ins.db.Close()
+}
+
+func (ins *Inspector) loadTables() error {
+	rows, err := ins.db.Query(`SELECT schemaname, tablename FROM pg_tables WHERE schemaname NOT IN ('pg_catalog', 'information_schema')`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var schema, name string
+		err = rows.Scan(&schema, &name)
+		if err != nil {
+			return err
+		}
+		ins.tables[name] = pq.QuoteIdentifier(schema) + "." + pq.QuoteIdentifier(name)
+	}
+	return rows.Err()
+}
+
+// table returns the qualified name of a table, given its
+// unqualified name.
+func (ins *Inspector) table(name string) (string, error) {
+	q, ok := ins.tables[name]
+	if !ok {
+		names := []string{}
+		for n := range ins.tables {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("no table %q in database; tables are %s", name, strings.Join(names, ", "))
+	}
+	return q, nil
+}
+
+// Count returns the number of rows in a table that match where,
+// which is an SQL condition using $1, $2, ... for args. An empty
+// where counts every row.
+func (ins *Inspector) Count(table string, where string, args ...interface{}) (int, error) {
+	q, err := ins.table(table)
+	if err != nil {
+		return 0, err
+	}
+	query := "SELECT count(*) FROM " + q
+	if where != "" {
+		query += " WHERE " + where
+	}
+
+	var n int
+	err = ins.db.QueryRow(query, args...).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", query, err)
+	}
+	return n, nil
+}
+
+// ExpectCount returns an error unless exactly want rows in table
+// match where.
+func (ins *Inspector) ExpectCount(want int, table string, where string, args ...interface{}) error {
+	n, err := ins.Count(table, where, args...)
+	if err != nil {
+		return err
+	}
+	if n != want {
+		return fmt.Errorf("expected %d rows in %s%s, got %d", want, table, describe(where, args), n)
+	}
+	return nil
+}
+
+// ExpectNone returns an error if any rows in table match where.
+func (ins *Inspector) ExpectNone(table string, where string, args ...interface{}) error {
+	return ins.ExpectCount(0, table, where, args...)
+}
+
+// ExpectOne returns an error unless exactly one row in table
+// matches where.
+func (ins *Inspector) ExpectOne(table string, where string, args ...interface{}) error {
+	return ins.ExpectCount(1, table, where, args...)
+}
+
+func describe(where string, args []interface{}) string {
+	if where == "" {
+		return ""
+	}
+	if len(args) == 0 {
+		return " where " + where
+	}
+	return fmt.Sprintf(" where %s %v", where, args)
+}
@@ -0,0 +1,177 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package history keeps the results of test runs as JSON files in a
+// directory, one file per run, and reports on how the results have
+// changed across runs.
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// Run is the stored record of one test run.
+type Run struct {
+	// ID identifies the run, and names its file in the store.
+	ID string `json:"id"`
+
+	// Time is when the run started.
+	Time time.Time `json:"time"`
+
+	// SUTVersion is the version of the API that was tested, or
+	// "unknown".
+	SUTVersion string `json:"sut_version"`
+
+	// Root is the root URL that was tested.
+	Root string `json:"root"`
+
+	// Results are the results of each test.
+	Results []*Result `json:"results"`
+}
+
+// Result is the stored result of one test in a run.
+type Result struct {
+	Suite   string `json:"suite"`
+	Element string `json:"element"`
+	ID      string `json:"id"`
+
+	// Outcome is the test's overall outcome. If the test was run
+	// more than once, it is the outcome shown in the results
+	// table.
+	Outcome testresult.Outcome `json:"outcome"`
+
+	// Runs and Passes count how many times the test was run in
+	// the run, and how many of those passed.
+	Runs   int `json:"runs"`
+	Passes int `json:"passes"`
+
+	// Duration is how long the test took, averaged over its runs.
+	Duration time.Duration `json:"duration"`
+
+	// FailStep and FailError describe the failure, if any.
+	FailStep  string `json:"fail_step,omitempty"`
+	FailError string `json:"fail_error,omitempty"`
+}
+
+// Key identifies the test that a result is for, across runs.
+func (r *Result) Key() string {
+	return r.Suite + ":" + r.Element + ":" + r.ID
+}
+
+// NewRun returns a Run with an ID based on its start time.
+func NewRun(start time.Time, sutVersion string, root string) *Run {
+	if sutVersion == "" {
+		sutVersion = "unknown"
+	}
+	return &Run{
+		ID:         start.UTC().Format("20060102-150405.000"),
+		Time:       start,
+		SUTVersion: sutVersion,
+		Root:       root,
+		Results:    []*Result{},
+	}
+}
+
+// Add records the result of a test. rs are all of its runs, and
+// shown is the one shown in the results table; rs is empty if the
+// test was skipped without being run.
+func (run *Run) Add(shown *testresult.TestResult, rs []*testresult.TestResult) {
+	r := &Result{
+		Suite:    shown.Suite,
+		Element:  shown.Element,
+		ID:       shown.ID,
+		Outcome:  shown.Outcome(),
+		Runs:     len(rs),
+		FailStep: shown.FailStep,
+	}
+	if shown.FailError != nil {
+		r.FailError = shown.FailError.Error()
+	}
+
+	var total time.Duration
+	for _, x := range rs {
+		if x.Success {
+			r.Passes++
+		}
+		total += x.Duration
+	}
+	if len(rs) > 0 {
+		r.Duration = total / time.Duration(len(rs))
+	}
+
+	run.Results = append(run.Results, r)
+}
+
+// Store is a directory of run files.
+type Store struct {
+	dir string
+}
+
+// Open returns the store in a directory, creating it if needed.
+func Open(dir string) (*Store, error) {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir}, nil
+}
+
+// Save writes a run to the store, replacing any earlier run with
+// the same ID.
+func (s *Store) Save(run *Run) error {
+	if run.ID == "" || strings.ContainsAny(run.ID, `/\`) {
+		return fmt.Errorf("invalid run ID %q", run.ID)
+	}
+
+	b, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file first, so that a report never
+	// sees a partly written run
+	path := filepath.Join(s.dir, run.ID+".json")
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads all of the runs in the store, oldest first.
+func (s *Store) Load() ([]*Run, error) {
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	runs := []*Run{}
+	for _, fi := range files {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(s.dir, fi.Name())
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		run := &Run{}
+		err = json.Unmarshal(b, run)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", path, err)
+		}
+		runs = append(runs, run)
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	return runs, nil
+}
@@ -0,0 +1,359 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package history
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// ReportConfig configures a report.
+type ReportConfig struct {
+	// Window is how many of the latest runs to look at for
+	// flakiness and duration trends. The whole history is always
+	// used to find when a failure started.
+	Window int
+
+	// MaxRows is the most rows to show in each list.
+	MaxRows int
+
+	// SlowdownRatio is how much longer than its earlier median
+	// duration a test must take in the latest run to be listed
+	// as slowing down, e.g. 1.5 for 50% longer.
+	SlowdownRatio float64
+
+	// MinSlowdown is the smallest absolute slowdown to list, so
+	// that noise in very fast tests is ignored.
+	MinSlowdown time.Duration
+}
+
+// isFailing returns whether an outcome makes a run red.
+func isFailing(o testresult.Outcome) bool {
+	return o == testresult.Fail || o == testresult.XPass
+}
+
+// isPassing returns whether an outcome is as expected.
+func isPassing(o testresult.Outcome) bool {
+	return o == testresult.Pass || o == testresult.XFail
+}
+
+// indexed is a run with its results by test key.
+type indexed struct {
+	*Run
+	byKey map[string]*Result
+}
+
+func index(runs []*Run) []*indexed {
+	ixs := []*indexed{}
+	for _, run := range runs {
+		ix := &indexed{Run: run, byKey: map[string]*Result{}}
+		for _, r := range run.Results {
+			ix.byKey[r.Key()] = r
+		}
+		ixs = append(ixs, ix)
+	}
+	return ixs
+}
+
+// Report writes a report on the runs, which must be oldest first:
+// what changed in the latest run, when current failures started,
+// which tests are flaky, and which are slowing down.
+func Report(w io.Writer, runs []*Run, cfg *ReportConfig) {
+	if len(runs) == 0 {
+		fmt.Fprintf(w, "No runs recorded.\n")
+		return
+	}
+
+	ixs := index(runs)
+	latest := ixs[len(ixs)-1]
+
+	fmt.Fprintf(w, "%d runs recorded, from %s to %s\n", len(runs), runs[0].Time.Format(time.RFC3339), latest.Time.Format(time.RFC3339))
+	fmt.Fprintf(w, "Latest run: %s\n\n", describeRun(latest.Run))
+
+	reportChanges(w, ixs, cfg)
+
+	window := ixs
+	if cfg.Window > 0 && len(window) > cfg.Window {
+		window = window[len(window)-cfg.Window:]
+	}
+	reportFlaky(w, window, cfg)
+	reportDurations(w, window, cfg)
+}
+
+// describeRun summarizes a run in one line.
+func describeRun(run *Run) string {
+	counts := map[testresult.Outcome]int{}
+	for _, r := range run.Results {
+		counts[r.Outcome]++
+	}
+	return fmt.Sprintf("%s (version %s, %s): %d passed, %d failed, %d skipped, %d expected failures, %d unexpected passes",
+		run.ID, run.SUTVersion, run.Time.Format(time.RFC3339),
+		counts[testresult.Pass], counts[testresult.Fail], counts[testresult.Skip], counts[testresult.XFail], counts[testresult.XPass])
+}
+
+// reportChanges lists the tests that started failing or were fixed
+// in the latest run, and the tests that are still failing, with
+// the run in which each failure first appeared.
+func reportChanges(w io.Writer, ixs []*indexed, cfg *ReportConfig) {
+	latest := ixs[len(ixs)-1]
+	var prev *indexed
+	if len(ixs) > 1 {
+		prev = ixs[len(ixs)-2]
+		fmt.Fprintf(w, "Compared with the previous run: %s\n", describeRun(prev.Run))
+	}
+
+	newlyFailing, stillFailing, fixed := []string{}, []string{}, []string{}
+	for _, r := range latest.Results {
+		var before *Result
+		if prev != nil {
+			before = prev.byKey[r.Key()]
+		}
+		switch {
+		case isFailing(r.Outcome) && (before == nil || !isFailing(before.Outcome)):
+			newlyFailing = append(newlyFailing, failingSince(ixs, r.Key()))
+		case isFailing(r.Outcome):
+			stillFailing = append(stillFailing, failingSince(ixs, r.Key()))
+		case isPassing(r.Outcome) && before != nil && isFailing(before.Outcome):
+			fixed = append(fixed, fmt.Sprintf("%s\thad failed for %s", r.Key(), describeStreak(ixs[:len(ixs)-1], r.Key())))
+		}
+	}
+
+	printList(w, "Newly failing", newlyFailing, cfg.MaxRows)
+	printList(w, "Fixed", fixed, cfg.MaxRows)
+	printList(w, "Still failing", stillFailing, cfg.MaxRows)
+}
+
+// failingSince describes when a test that is failing in the latest
+// run started failing.
+func failingSince(ixs []*indexed, key string) string {
+	return fmt.Sprintf("%s\tfailing for %s", key, describeStreak(ixs, key))
+}
+
+// describeStreak describes the streak of failing runs that ends
+// with the last of ixs: when it started, and the last run before
+// it in which the test passed.
+func describeStreak(ixs []*indexed, key string) string {
+	first := len(ixs) - 1
+	for first > 0 {
+		r := ixs[first-1].byKey[key]
+		if r == nil || !isFailing(r.Outcome) {
+			break
+		}
+		first--
+	}
+
+	n := len(ixs) - first
+	start := ixs[first]
+	desc := fmt.Sprintf("%d runs, since %s (version %s, %s)", n, start.ID, start.SUTVersion, start.Time.Format("2006-01-02"))
+	if n == 1 {
+		desc = fmt.Sprintf("1 run, in %s (version %s, %s)", start.ID, start.SUTVersion, start.Time.Format("2006-01-02"))
+	}
+
+	for i := first - 1; i >= 0; i-- {
+		r := ixs[i].byKey[key]
+		if r != nil && isPassing(r.Outcome) {
+			return fmt.Sprintf("%s; last passed in %s (version %s)", desc, ixs[i].ID, ixs[i].SUTVersion)
+		}
+	}
+	if first == 0 {
+		return desc + "; has not passed in any recorded run"
+	}
+	return desc + "; new test"
+}
+
+// flakiness describes how a test's outcomes varied over the
+// window.
+type flakiness struct {
+	key    string
+	runs   int
+	passes int
+	flips  int
+	varied int
+}
+
+// reportFlaky lists the tests whose outcome flipped between passing
+// and failing more than once, or varied within a single run.
+func reportFlaky(w io.Writer, window []*indexed, cfg *ReportConfig) {
+	fs := map[string]*flakiness{}
+	keys := []string{}
+	last := map[string]bool{}
+	for _, ix := range window {
+		for _, r := range ix.Results {
+			if r.Runs == 0 || r.Outcome == testresult.Skip {
+				continue
+			}
+			f, ok := fs[r.Key()]
+			if !ok {
+				f = &flakiness{key: r.Key()}
+				fs[r.Key()] = f
+				keys = append(keys, r.Key())
+			}
+			failing := isFailing(r.Outcome)
+			if f.runs > 0 && last[r.Key()] != failing {
+				f.flips++
+			}
+			last[r.Key()] = failing
+			f.runs++
+			if !failing {
+				f.passes++
+			}
+			if r.Passes > 0 && r.Passes < r.Runs {
+				f.varied++
+			}
+		}
+	}
+
+	flaky := []*flakiness{}
+	for _, k := range keys {
+		f := fs[k]
+		if f.flips >= 2 || f.varied > 0 {
+			flaky = append(flaky, f)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		return flaky[i].flips+flaky[i].varied > flaky[j].flips+flaky[j].varied
+	})
+
+	rows := []string{}
+	for _, f := range flaky {
+		rows = append(rows, fmt.Sprintf("%s\tpassed %d/%d runs\t%d flips\t%d runs with mixed repeats", f.key, f.passes, f.runs, f.flips, f.varied))
+	}
+	printList(w, fmt.Sprintf("Flaky over the last %d runs", len(window)), rows, cfg.MaxRows)
+}
+
+// trend is a test's durations over the window.
+type trend struct {
+	key       string
+	durations []time.Duration
+	median    time.Duration
+	latest    time.Duration
+}
+
+// reportDurations lists the tests that took notably longer in the
+// latest run than their median over the earlier runs in the
+// window, with a sparkline of their durations.
+func reportDurations(w io.Writer, window []*indexed, cfg *ReportConfig) {
+	if len(window) < 2 {
+		return
+	}
+
+	latest := window[len(window)-1]
+	trends := []*trend{}
+	for _, r := range latest.Results {
+		if r.Runs == 0 {
+			continue
+		}
+		t := &trend{key: r.Key(), latest: r.Duration}
+		for _, ix := range window {
+			if x := ix.byKey[r.Key()]; x != nil && x.Runs > 0 {
+				t.durations = append(t.durations, x.Duration)
+			}
+		}
+		if len(t.durations) < 2 {
+			continue
+		}
+		t.median = median(t.durations[:len(t.durations)-1])
+		if float64(t.latest) >= cfg.SlowdownRatio*float64(t.median) && t.latest-t.median >= cfg.MinSlowdown {
+			trends = append(trends, t)
+		}
+	}
+	sort.SliceStable(trends, func(i, j int) bool {
+		return float64(trends[i].latest)/float64(trends[i].median+1) > float64(trends[j].latest)/float64(trends[j].median+1)
+	})
+
+	rows := []string{}
+	for _, t := range trends {
+		rows = append(rows, fmt.Sprintf("%s\t%v -> %v\t%s", t.key, t.median.Round(time.Millisecond), t.latest.Round(time.Millisecond), sparkline(t.durations)))
+	}
+	printList(w, fmt.Sprintf("Slowing down (latest vs. median of the previous %d runs)", len(window)-1), rows, cfg.MaxRows)
+}
+
+// median returns the median of some durations.
+func median(ds []time.Duration) time.Duration {
+	s := append([]time.Duration{}, ds...)
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	if len(s)%2 == 1 {
+		return s[len(s)/2]
+	}
+	return (s[len(s)/2-1] + s[len(s)/2]) / 2
+}
+
+// sparkBars are the characters used by sparkline, from lowest to
+// highest.
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// sparkline draws durations as a row of bars, scaled between the
+// smallest and largest.
+func sparkline(ds []time.Duration) string {
+	lo, hi := ds[0], ds[0]
+	for _, d := range ds {
+		if d < lo {
+			lo = d
+		}
+		if d > hi {
+			hi = d
+		}
+	}
+
+	var b strings.Builder
+	for _, d := range ds {
+		i := 0
+		if hi > lo {
+			i = int(float64(d-lo) / float64(hi-lo) * float64(len(sparkBars)-1))
+		}
+		b.WriteRune(sparkBars[i])
+	}
+	return b.String()
+}
+
+// printList prints a titled list of tab-separated rows, if there
+// are any, showing at most max of them.
+func printList(w io.Writer, title string, rows []string, max int) {
+	if len(rows) == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "\n%s (%d):\n", title, len(rows))
+	tw := tabwriter.NewWriter(w, 8, 4, 2, ' ', 0)
+	for i, row := range rows {
+		if max > 0 && i == max {
+			fmt.Fprintf(tw, "  ... and %d more\n", len(rows)-max)
+			break
+		}
+		fmt.Fprintf(tw, "  %s\n", row)
+	}
+	tw.Flush()
+}
+
+// TestReport writes the history of one test, identified by its
+// key ("suite:element:id"), with its outcome and duration in each
+// run.
+func TestReport(w io.Writer, runs []*Run, key string) {
+	tw := tabwriter.NewWriter(w, 8, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "RUN\tVERSION\tTIME\tOUTCOME\tPASSES\tDURATION\tFAILURE\n")
+	found := false
+	for _, run := range runs {
+		for _, r := range run.Results {
+			if r.Key() != key {
+				continue
+			}
+			found = true
+			failure := ""
+			if r.FailStep != "" {
+				failure = fmt.Sprintf("step %s: %s", r.FailStep, r.FailError)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d/%d\t%v\t%s\n", run.ID, run.SUTVersion, run.Time.Format(time.RFC3339), r.Outcome, r.Passes, r.Runs, r.Duration.Round(time.Millisecond), failure)
+		}
+	}
+	if !found {
+		fmt.Fprintf(w, "No runs recorded for %s.\n", key)
+		return
+	}
+	tw.Flush()
+}
@@ -0,0 +1,106 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package htmlreport
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// diffRow is one row of a side-by-side diff. A side with no line
+// number is blank, because the other side's line was added or
+// removed.
+type diffRow struct {
+	LeftNo, RightNo int
+	Left, Right     string
+
+	// Kind is "same", "changed", "removed" or "added".
+	Kind string
+}
+
+// sideBySide diffs the wanted and received JSON line by line, after
+// formatting both the same way so that only differences in content
+// are shown.
+func sideBySide(wanted string, got string) []diffRow {
+	// end both with a newline, so that a last line that is the
+	// same on both sides is seen as the same
+	a := strings.TrimSuffix(prettyJSON([]byte(wanted)), "\n") + "\n"
+	b := strings.TrimSuffix(prettyJSON([]byte(got)), "\n") + "\n"
+
+	dmp := diffmatchpatch.New()
+	ca, cb, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(ca, cb, false), lines)
+
+	rows := []diffRow{}
+	leftNo, rightNo := 0, 0
+	var removed, added []string
+
+	// flush pairs up a run of removed lines with the added lines
+	// that replace them
+	flush := func() {
+		for i := 0; i < len(removed) || i < len(added); i++ {
+			row := diffRow{Kind: "changed"}
+			if i < len(removed) {
+				leftNo++
+				row.LeftNo, row.Left = leftNo, removed[i]
+			} else {
+				row.Kind = "added"
+			}
+			if i < len(added) {
+				rightNo++
+				row.RightNo, row.Right = rightNo, added[i]
+			} else {
+				row.Kind = "removed"
+			}
+			rows = append(rows, row)
+		}
+		removed, added = nil, nil
+	}
+
+	for _, d := range diffs {
+		ls := splitLines(d.Text)
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			removed = append(removed, ls...)
+		case diffmatchpatch.DiffInsert:
+			added = append(added, ls...)
+		default:
+			flush()
+			for _, l := range ls {
+				leftNo++
+				rightNo++
+				rows = append(rows, diffRow{LeftNo: leftNo, RightNo: rightNo, Left: l, Right: l, Kind: "same"})
+			}
+		}
+	}
+	flush()
+
+	return rows
+}
+
+// prettyJSON formats JSON with sorted keys and indentation. Anything
+// that isn't JSON is returned as it is.
+func prettyJSON(b []byte) string {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	if len(bytes.TrimSpace(b)) == 0 || d.Decode(&v) != nil {
+		return string(b)
+	}
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return string(b)
+	}
+	return string(out)
+}
+
+// splitLines splits text into lines, ignoring a final newline.
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
@@ -0,0 +1,293 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package htmlreport writes test results as a single, self-contained
+// HTML file, with a summary by suite and element, filters by
+// outcome, and for each test, the requests it made and a
+// side-by-side diff of the wanted and received responses.
+package htmlreport
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// Run describes the run that a report is for.
+type Run struct {
+	// Root is the root URL that was tested.
+	Root string
+
+	// SUTVersion is the version of the API that was tested, if
+	// known.
+	SUTVersion string
+
+	// Started is when the run started, and Duration how long it
+	// took.
+	Started  time.Time
+	Duration time.Duration
+}
+
+// outcomes are the test outcomes, in the order they are shown,
+// with their labels.
+var outcomes = []struct {
+	Outcome testresult.Outcome
+	Label   string
+}{
+	{testresult.Fail, "failed"},
+	{testresult.XPass, "unexpectedly passed"},
+	{testresult.Pass, "passed"},
+	{testresult.XFail, "failed as expected"},
+	{testresult.Skip, "skipped"},
+}
+
+// count is the number of tests with an outcome.
+type count struct {
+	Outcome testresult.Outcome
+	Label   string
+	N       int
+}
+
+// group summarizes the tests of one element of a suite.
+type group struct {
+	Suite, Element string
+	Counts         []count
+	Anchor         string
+}
+
+// exchange is one request and response of a test, formatted for
+// display.
+type exchange struct {
+	Step, Method, URL, User string
+	RequestBody             string
+	Status                  int
+	ResponseBody            string
+	Truncated               bool
+	Error                   string
+}
+
+// test is one test's result, formatted for display.
+type test struct {
+	Anchor         string
+	Outcome        testresult.Outcome
+	Suite, Element string
+	ID             string
+	Tags           []string
+	Duration       time.Duration
+	FailStep       string
+	FailError      string
+	Reason, Issue  string
+	Transcript     []exchange
+	Diff           []diffRow
+	Open           bool
+	Expects        bool
+}
+
+// page is everything the template shows.
+type page struct {
+	Run       *Run
+	Generated time.Time
+	Total     int
+	Counts    []count
+	Groups    []*group
+	Tests     []*test
+}
+
+// Write writes the report on the results to w.
+func Write(w io.Writer, rs []*testresult.TestResult, run *Run) error {
+	p := &page{
+		Run:       run,
+		Generated: time.Now(),
+		Total:     len(rs),
+		Counts:    countOutcomes(rs),
+	}
+
+	groups := map[[2]string]*group{}
+	groupRs := map[[2]string][]*testresult.TestResult{}
+	for i, r := range rs {
+		t := newTest(i, r)
+		p.Tests = append(p.Tests, t)
+
+		key := [2]string{r.Suite, r.Element}
+		if groups[key] == nil {
+			groups[key] = &group{Suite: r.Suite, Element: r.Element, Anchor: t.Anchor}
+			p.Groups = append(p.Groups, groups[key])
+		}
+		groupRs[key] = append(groupRs[key], r)
+	}
+	for key, g := range groups {
+		g.Counts = countOutcomes(groupRs[key])
+	}
+
+	return reportTemplate.Execute(w, p)
+}
+
+// countOutcomes counts the results with each outcome.
+func countOutcomes(rs []*testresult.TestResult) []count {
+	n := map[testresult.Outcome]int{}
+	for _, r := range rs {
+		n[r.Outcome()]++
+	}
+	cs := []count{}
+	for _, o := range outcomes {
+		cs = append(cs, count{Outcome: o.Outcome, Label: o.Label, N: n[o.Outcome]})
+	}
+	return cs
+}
+
+// newTest formats a result for display.
+func newTest(i int, r *testresult.TestResult) *test {
+	o := r.Outcome()
+	t := &test{
+		Anchor:   fmt.Sprintf("test-%d", i),
+		Outcome:  o,
+		Suite:    r.Suite,
+		Element:  r.Element,
+		ID:       r.ID,
+		Tags:     r.Tags,
+		Duration: r.Duration.Round(time.Millisecond),
+		FailStep: r.FailStep,
+		Reason:   r.Reason,
+		Issue:    r.Issue,
+		Open:     o == testresult.Fail || o == testresult.XPass,
+	}
+	if r.FailError != nil {
+		t.FailError = r.FailError.Error()
+	}
+
+	for _, ex := range r.Transcript {
+		t.Transcript = append(t.Transcript, exchange{
+			Step:         ex.Step,
+			Method:       ex.Method,
+			URL:          ex.URL,
+			User:         ex.User,
+			RequestBody:  prettyJSON([]byte(ex.RequestBody)),
+			Status:       ex.Status,
+			ResponseBody: prettyJSON(ex.ResponseBody),
+			Truncated:    ex.Truncated,
+			Error:        ex.Error,
+		})
+	}
+
+	// a diff only helps for a failed comparison
+	if (o == testresult.Fail || o == testresult.XFail) && r.Wanted != "" {
+		t.Expects = true
+		t.Diff = sideBySide(r.Wanted, string(r.Got))
+	}
+
+	return t
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(reportHTML))
+
+const reportHTML = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>peridot API test report</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.meta { color: #666; margin-bottom: 1.5em; }
+table { border-collapse: collapse; }
+th, td { padding: 0.25em 0.75em; text-align: left; border-bottom: 1px solid #eee; }
+td.n { text-align: right; }
+td.zero { color: #ccc; }
+.badge { display: inline-block; min-width: 5em; padding: 0.1em 0.5em; border-radius: 3px; font-size: 0.85em; font-weight: bold; text-align: center; color: #fff; }
+.fail { background: #c62828; } .xpass { background: #ef6c00; } .pass { background: #2e7d32; } .xfail { background: #6a1b9a; } .skip { background: #757575; }
+.filters { margin: 1.5em 0 1em; }
+.filters label { margin-right: 1em; cursor: pointer; }
+.filters input[type=search] { margin-left: 1em; padding: 0.2em 0.4em; width: 20em; }
+details.test { border: 1px solid #ddd; border-radius: 4px; margin: 0.4em 0; }
+details.test > summary { padding: 0.4em 0.6em; cursor: pointer; }
+details.test > div { padding: 0 1em 1em; }
+.name { font-family: monospace; }
+.dur, .tag { color: #777; font-size: 0.85em; margin-left: 0.5em; }
+.tag { border: 1px solid #bbb; border-radius: 3px; padding: 0 0.3em; }
+.failure { background: #fdecea; border-left: 4px solid #c62828; padding: 0.5em 0.8em; margin: 0.8em 0; }
+.note { background: #f3e5f5; border-left: 4px solid #6a1b9a; padding: 0.5em 0.8em; margin: 0.8em 0; }
+pre { background: #f6f8fa; padding: 0.5em; overflow-x: auto; max-height: 30em; margin: 0.3em 0; font-size: 0.85em; }
+.exchange { margin: 0.8em 0; }
+.exchange .req { font-family: monospace; font-weight: bold; }
+.exchange .status { font-family: monospace; margin-left: 0.5em; }
+table.diff { width: 100%; table-layout: fixed; font-family: monospace; font-size: 0.85em; }
+table.diff td { border: none; padding: 0 0.4em; white-space: pre-wrap; word-break: break-all; vertical-align: top; }
+table.diff td.no { width: 3em; color: #999; text-align: right; }
+table.diff tr.changed td.l, table.diff tr.removed td.l { background: #ffebe9; }
+table.diff tr.changed td.r, table.diff tr.added td.r { background: #e6ffec; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<h1>peridot API test report</h1>
+<div class="meta">
+  {{.Total}} tests against {{.Run.Root}}{{if .Run.SUTVersion}} (version {{.Run.SUTVersion}}){{end}},
+  started {{.Run.Started.Format "2006-01-02 15:04:05 MST"}}, took {{.Run.Duration}};
+  report generated {{.Generated.Format "2006-01-02 15:04:05 MST"}}
+</div>
+
+<p>{{range .Counts}}<span class="badge {{.Outcome}}">{{.N}} {{.Label}}</span> {{end}}</p>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Suite</th><th>Element</th>{{range .Counts}}<th>{{.Label}}</th>{{end}}</tr>
+{{range .Groups}}<tr><td>{{.Suite}}</td><td><a href="#{{.Anchor}}">{{.Element}}</a></td>{{range .Counts}}<td class="n{{if not .N}} zero{{end}}">{{.N}}</td>{{end}}</tr>
+{{end}}</table>
+
+<h2>Tests</h2>
+<div class="filters">
+  Show:
+  {{range .Counts}}<label><input type="checkbox" class="outcome-filter" value="{{.Outcome}}" checked> {{.Label}} ({{.N}})</label>{{end}}
+  <input type="search" id="search" placeholder="Filter by name">
+</div>
+
+{{range .Tests}}<details class="test" id="{{.Anchor}}" data-outcome="{{.Outcome}}" data-name="{{.Suite}} {{.Element}} {{.ID}}"{{if .Open}} open{{end}}>
+<summary><span class="badge {{.Outcome}}">{{.Outcome}}</span> <span class="name">{{.Suite}} : {{.Element}} : {{.ID}}</span>{{if .Duration}}<span class="dur">{{.Duration}}</span>{{end}}{{range .Tags}}<span class="tag">{{.}}</span>{{end}}</summary>
+<div>
+{{if .Reason}}<div class="note">{{if eq .Outcome "skip"}}Skipped{{else}}Expected to fail{{end}}: {{.Reason}}{{if .Issue}} (<a href="{{.Issue}}">{{.Issue}}</a>){{end}}</div>{{end}}
+{{if .FailStep}}<div class="failure">Failed at step {{.FailStep}}{{if .FailError}}: {{.FailError}}{{else}}: the response did not match{{end}}</div>{{end}}
+{{if .Expects}}<h4>Wanted vs. got</h4>
+<table class="diff">
+<tr><th class="no"></th><th>Wanted</th><th class="no"></th><th>Got</th></tr>
+{{range .Diff}}<tr class="{{.Kind}}"><td class="no">{{if .LeftNo}}{{.LeftNo}}{{end}}</td><td class="l">{{.Left}}</td><td class="no">{{if .RightNo}}{{.RightNo}}{{end}}</td><td class="r">{{.Right}}</td></tr>
+{{end}}</table>{{end}}
+{{if .Transcript}}<h4>Steps</h4>
+{{range .Transcript}}<div class="exchange">
+  <div>Step {{.Step}}: <span class="req">{{.Method}} {{.URL}}</span> as {{.User}}{{if .Status}}<span class="status">&rarr; {{.Status}}</span>{{end}}</div>
+  {{if .RequestBody}}<div>Request:</div><pre>{{.RequestBody}}</pre>{{end}}
+  {{if .Error}}<div class="failure">No response: {{.Error}}</div>{{else}}<div>Response{{if .Truncated}} (truncated){{end}}:</div><pre>{{.ResponseBody}}</pre>{{end}}
+</div>
+{{end}}{{else}}<p>No requests were recorded for this test.</p>{{end}}
+</div>
+</details>
+{{end}}
+
+<script>
+(function() {
+  var filters = document.querySelectorAll(".outcome-filter");
+  var search = document.getElementById("search");
+  var tests = document.querySelectorAll("details.test");
+  function apply() {
+    var shown = {};
+    for (var i = 0; i < filters.length; i++) {
+      shown[filters[i].value] = filters[i].checked;
+    }
+    var q = search.value.toLowerCase();
+    for (var j = 0; j < tests.length; j++) {
+      var t = tests[j];
+      var ok = shown[t.getAttribute("data-outcome")] &&
+        t.getAttribute("data-name").toLowerCase().indexOf(q) >= 0;
+      t.classList.toggle("hidden", !ok);
+    }
+  }
+  for (var i = 0; i < filters.length; i++) {
+    filters[i].addEventListener("change", apply);
+  }
+  search.addEventListener("input", apply);
+})();
+</script>
+</body>
+</html>
+`
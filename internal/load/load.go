@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package load
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Config describes a load run.
+type Config struct {
+	// Root is the root URL of the peridot API.
+	Root string
+
+	// Mix is the set of requests to choose from.
+	Mix []Request
+
+	// Concurrency is the number of workers sending requests.
+	Concurrency int
+
+	// Rate is the target number of requests per second across
+	// all workers. If zero, each worker sends its next request
+	// as soon as the previous one completes.
+	Rate float64
+
+	// Duration is how long to keep sending requests.
+	Duration time.Duration
+
+	// Seed is used to choose requests from the mix.
+	Seed int64
+}
+
+// Sample records the outcome of a single request.
+type Sample struct {
+	Route    string
+	Method   string
+	Path     string
+	Identity string
+	Start    time.Time
+	Latency  time.Duration
+	Code     int
+	Err      error
+}
+
+// Failed indicates whether the sample counts as an error: either
+// the request could not be made, or the status code was not the
+// expected one.
+func (s *Sample) Failed() bool {
+	return s.Err != nil
+}
+
+// Run sends requests according to cfg until its duration has
+// elapsed, and returns every sample that was recorded.
+func Run(cfg *Config) ([]*Sample, error) {
+	if cfg.Concurrency < 1 {
+		return nil, fmt.Errorf("concurrency must be at least 1, got %d", cfg.Concurrency)
+	}
+	totalWeight := 0
+	for _, r := range cfg.Mix {
+		if r.Weight < 0 {
+			return nil, fmt.Errorf("negative weight %d for %s %s", r.Weight, r.Method, r.Path)
+		}
+		totalWeight += r.Weight
+	}
+	if totalWeight == 0 {
+		return nil, fmt.Errorf("request mix has no requests with positive weight")
+	}
+
+	// use a dedicated transport so that connections are reused
+	// across workers, rather than being limited by the default
+	// per-host idle connection pool
+	client := &http.Client{
+		Transport: &http.Transport{
+			MaxIdleConns:        cfg.Concurrency,
+			MaxIdleConnsPerHost: cfg.Concurrency,
+		},
+	}
+
+	// stop is closed once the duration has elapsed
+	stop := make(chan struct{})
+	timer := time.AfterFunc(cfg.Duration, func() { close(stop) })
+	defer timer.Stop()
+
+	// if a rate is set, hand out tokens at that rate; otherwise
+	// tokens is nil and workers run as fast as they can
+	var tokens chan struct{}
+	if cfg.Rate > 0 {
+		tokens = make(chan struct{}, cfg.Concurrency)
+		interval := time.Duration(float64(time.Second) / cfg.Rate)
+		if interval < 1 {
+			// the ticker needs a positive interval; at such a
+			// rate, workers are never left waiting for a token
+			interval = 1
+		}
+		go func() {
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-stop:
+					return
+				case <-ticker.C:
+					select {
+					case tokens <- struct{}{}:
+					default:
+						// all workers are busy; drop this token
+						// rather than building up a backlog
+					}
+				}
+			}
+		}()
+	}
+
+	var mu sync.Mutex
+	samples := []*Sample{}
+	var wg sync.WaitGroup
+
+	for i := 0; i < cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(cfg.Seed + int64(worker)))
+			mine := []*Sample{}
+		loop:
+			for {
+				if tokens != nil {
+					select {
+					case <-tokens:
+					case <-stop:
+						break loop
+					}
+				} else {
+					select {
+					case <-stop:
+						break loop
+					default:
+					}
+				}
+				r := pick(cfg.Mix, totalWeight, rnd)
+				mine = append(mine, send(client, cfg.Root, r))
+			}
+			mu.Lock()
+			samples = append(samples, mine...)
+			mu.Unlock()
+		}(i)
+	}
+
+	wg.Wait()
+	return samples, nil
+}
+
+// pick chooses a request from the mix, in proportion to weight.
+func pick(mix []Request, totalWeight int, rnd *rand.Rand) *Request {
+	n := rnd.Intn(totalWeight)
+	for i := range mix {
+		n -= mix[i].Weight
+		if n < 0 {
+			return &mix[i]
+		}
+	}
+	return &mix[len(mix)-1]
+}
+
+// send makes one request and records how long it took.
+func send(client *http.Client, root string, r *Request) *Sample {
+	s := &Sample{
+		Route:    r.Route,
+		Method:   r.Method,
+		Path:     r.Path,
+		Identity: r.Identity,
+	}
+
+	var body io.Reader
+	if r.Body != "" {
+		body = strings.NewReader(r.Body)
+	}
+	req, err := http.NewRequest(r.Method, root+r.Path, body)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	if r.Body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	utils.AddAuthHeader(nil, "", req, r.Identity)
+
+	s.Start = time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		s.Latency = time.Since(s.Start)
+		s.Err = err
+		return s
+	}
+	// read the full body so the latency includes the transfer
+	// and the connection can be reused
+	_, err = io.Copy(ioutil.Discard, resp.Body)
+	resp.Body.Close()
+	s.Latency = time.Since(s.Start)
+	s.Code = resp.StatusCode
+	if err != nil {
+		s.Err = err
+		return s
+	}
+
+	if s.Code != r.Code {
+		s.Err = fmt.Errorf("expected HTTP status code %d, got %d", r.Code, s.Code)
+	}
+
+	return s
+}
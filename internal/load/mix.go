@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package load
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Request describes one kind of request that can be made as
+// part of a load run.
+type Request struct {
+	// Route is the route template that the request is reported
+	// under, e.g. "/repos/{id}/branches".
+	Route string `json:"route"`
+
+	// Method is the HTTP method, e.g. "GET".
+	Method string `json:"method"`
+
+	// Path is the concrete path to request, relative to the
+	// root URL, e.g. "/repos/2/branches".
+	Path string `json:"path"`
+
+	// Body is the request body, if any.
+	Body string `json:"body,omitempty"`
+
+	// Identity is the github username to send the request as,
+	// as understood by utils.AddAuthHeader.
+	Identity string `json:"identity"`
+
+	// Weight is the relative frequency of this request within
+	// the mix. Requests with zero weight are never sent.
+	Weight int `json:"weight"`
+
+	// Code is the HTTP status code that counts as success.
+	Code int `json:"code"`
+}
+
+// DefaultMix returns the built-in request mix. It is read-heavy,
+// and only refers to objects created by fixtures.SetupFixture so
+// that every request can succeed against a freshly set up DB.
+func DefaultMix() []Request {
+	return []Request{
+		{"/hello", "GET", "/hello", "", "none", 2, 200},
+		{"/users", "GET", "/users", "", "admin", 1, 200},
+		{"/users/{id}", "GET", "/users/2", "", "operator", 1, 200},
+		{"/projects", "GET", "/projects", "", "viewer", 4, 200},
+		{"/projects", "POST", "/projects", `{"name": "loadtest", "fullname": "The loadtest Project"}`, "operator", 1, 201},
+		{"/projects/{id}", "GET", "/projects/2", "", "viewer", 3, 200},
+		{"/subprojects", "GET", "/subprojects", "", "viewer", 2, 200},
+		{"/projects/{id}/subprojects", "GET", "/projects/2/subprojects", "", "commenter", 2, 200},
+		{"/subprojects/{id}", "GET", "/subprojects/2", "", "viewer", 2, 200},
+		{"/repos", "GET", "/repos", "", "viewer", 3, 200},
+		{"/subprojects/{id}/repos", "GET", "/subprojects/2/repos", "", "viewer", 2, 200},
+		{"/repos/{id}", "GET", "/repos/2", "", "viewer", 2, 200},
+		{"/repos/{id}/branches", "GET", "/repos/2/branches", "", "viewer", 3, 200},
+		{"/repos/{id}/branches/{branch}", "GET", "/repos/2/branches/dev-2.1", "", "viewer", 3, 200},
+		{"/repopulls/{id}", "GET", "/repopulls/5", "", "viewer", 2, 200},
+		{"/repopulls/{id}/jobs", "GET", "/repopulls/4/jobs", "", "viewer", 3, 200},
+		{"/repopulls/{id}/jobs", "POST", "/repopulls/3/jobs", `{"agent_id": 1, "priorjob_ids": [], "is_ready": false, "config": {}}`, "operator", 1, 201},
+		{"/jobs/{id}", "GET", "/jobs/4", "", "viewer", 2, 200},
+		{"/agents", "GET", "/agents", "", "viewer", 2, 200},
+		{"/agents/{id}", "GET", "/agents/2", "", "viewer", 2, 200},
+	}
+}
+
+// ReadMix reads a request mix from a JSON file containing an
+// array of Requests. Every request must have a method, a path, a
+// known identity (or "none") and a valid expected status code.
+func ReadMix(filename string) ([]Request, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var mix []Request
+	err = json.Unmarshal(b, &mix)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing request mix %s: %v", filename, err)
+	}
+
+	for i, r := range mix {
+		switch {
+		case r.Method == "":
+			return nil, fmt.Errorf("request %d in mix %s has no method", i, filename)
+		case !strings.HasPrefix(r.Path, "/"):
+			return nil, fmt.Errorf("request %d in mix %s has path %q, which does not start with /", i, filename, r.Path)
+		case !utils.KnownUser(r.Identity):
+			return nil, fmt.Errorf("request %d in mix %s (%s %s) has unknown identity %q; use none, admin, operator, commenter, viewer or disabled", i, filename, r.Method, r.Path, r.Identity)
+		case r.Code < 100 || r.Code > 599:
+			return nil, fmt.Errorf("request %d in mix %s (%s %s) has no valid expected status code", i, filename, r.Method, r.Path)
+		}
+	}
+
+	return mix, nil
+}
@@ -0,0 +1,214 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package load
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// histogramBounds are the upper bounds of the latency histogram
+// buckets. Anything slower than the last bound falls into a final
+// overflow bucket.
+var histogramBounds = []time.Duration{
+	1 * time.Millisecond,
+	2 * time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	20 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	200 * time.Millisecond,
+	500 * time.Millisecond,
+	1 * time.Second,
+	2 * time.Second,
+}
+
+// RouteStats summarizes the samples for one method and route
+// template.
+type RouteStats struct {
+	Method string
+	Route  string
+
+	Count  int
+	Errors int
+
+	// Throughput is the number of requests per second for this
+	// route over the whole run.
+	Throughput float64
+
+	P50 time.Duration
+	P90 time.Duration
+	P99 time.Duration
+	Max time.Duration
+
+	// Histogram holds the count of samples in each bucket; see
+	// histogramBounds. It has one more entry than there are
+	// bounds, for the overflow bucket.
+	Histogram []int
+}
+
+// ErrorRate returns the fraction of requests that failed.
+func (rs *RouteStats) ErrorRate() float64 {
+	if rs.Count == 0 {
+		return 0
+	}
+	return float64(rs.Errors) / float64(rs.Count)
+}
+
+// Summarize groups the samples by method and route template, and
+// computes statistics for each group. elapsed is the wall-clock
+// duration of the run, used to compute throughput. The results
+// are sorted by route and then method.
+func Summarize(samples []*Sample, elapsed time.Duration) []*RouteStats {
+	groups := map[string][]*Sample{}
+	for _, s := range samples {
+		key := s.Route + " " + s.Method
+		groups[key] = append(groups[key], s)
+	}
+
+	allStats := []*RouteStats{}
+	for _, g := range groups {
+		allStats = append(allStats, summarizeGroup(g, elapsed))
+	}
+
+	sort.Slice(allStats, func(i, j int) bool {
+		if allStats[i].Route != allStats[j].Route {
+			return allStats[i].Route < allStats[j].Route
+		}
+		return allStats[i].Method < allStats[j].Method
+	})
+	return allStats
+}
+
+// summarizeGroup computes the statistics for samples that all
+// share the same method and route.
+func summarizeGroup(samples []*Sample, elapsed time.Duration) *RouteStats {
+	rs := &RouteStats{
+		Method:    samples[0].Method,
+		Route:     samples[0].Route,
+		Count:     len(samples),
+		Histogram: make([]int, len(histogramBounds)+1),
+	}
+
+	latencies := make([]time.Duration, 0, len(samples))
+	for _, s := range samples {
+		if s.Failed() {
+			rs.Errors++
+		}
+		latencies = append(latencies, s.Latency)
+		rs.Histogram[bucket(s.Latency)]++
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	rs.P50 = percentile(latencies, 50)
+	rs.P90 = percentile(latencies, 90)
+	rs.P99 = percentile(latencies, 99)
+	rs.Max = latencies[len(latencies)-1]
+	if elapsed > 0 {
+		rs.Throughput = float64(rs.Count) / elapsed.Seconds()
+	}
+
+	return rs
+}
+
+// percentile returns the nearest-rank percentile p of the sorted
+// latencies.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// bucket returns the index of the histogram bucket for latency d.
+func bucket(d time.Duration) int {
+	for i, b := range histogramBounds {
+		if d <= b {
+			return i
+		}
+	}
+	return len(histogramBounds)
+}
+
+// PrintSummary writes a table of the route statistics, followed
+// by the latency histogram for each route, to w.
+func PrintSummary(w io.Writer, allStats []*RouteStats, elapsed time.Duration) {
+	total, errors := 0, 0
+	for _, rs := range allStats {
+		total += rs.Count
+		errors += rs.Errors
+	}
+	fmt.Fprintf(w, "%d requests in %v (%.1f req/s), %d errors\n\n", total, elapsed.Round(time.Millisecond), float64(total)/elapsed.Seconds(), errors)
+
+	tw := tabwriter.NewWriter(w, 8, 4, 1, ' ', 0)
+	fmt.Fprintf(tw, "ROUTE\tMETHOD\tCOUNT\tREQ/S\tERR%%\tP50\tP90\tP99\tMAX\n")
+	for _, rs := range allStats {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%.1f\t%.1f\t%v\t%v\t%v\t%v\n", rs.Route, rs.Method, rs.Count, rs.Throughput, 100*rs.ErrorRate(), ms(rs.P50), ms(rs.P90), ms(rs.P99), ms(rs.Max))
+	}
+	tw.Flush()
+
+	for _, rs := range allStats {
+		fmt.Fprintf(w, "\n%s %s\n", rs.Method, rs.Route)
+		for i, n := range rs.Histogram {
+			if n == 0 {
+				continue
+			}
+			var label string
+			if i < len(histogramBounds) {
+				label = "<= " + ms(histogramBounds[i])
+			} else {
+				label = "> " + ms(histogramBounds[len(histogramBounds)-1])
+			}
+			bar := strings.Repeat("#", (n*40+rs.Count-1)/rs.Count)
+			fmt.Fprintf(w, "  %10s  %6d  %s\n", label, n, bar)
+		}
+	}
+}
+
+// ms formats a duration in milliseconds.
+func ms(d time.Duration) string {
+	return strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', 1, 64) + "ms"
+}
+
+// WriteCSV writes one row per sample to the named file.
+func WriteCSV(filename string, samples []*Sample) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"start", "route", "method", "path", "identity", "status", "latency_ms", "error"})
+	for _, s := range samples {
+		errstr := ""
+		if s.Err != nil {
+			errstr = s.Err.Error()
+		}
+		w.Write([]string{
+			s.Start.Format(time.RFC3339Nano),
+			s.Route,
+			s.Method,
+			s.Path,
+			s.Identity,
+			strconv.Itoa(s.Code),
+			strconv.FormatFloat(float64(s.Latency)/float64(time.Millisecond), 'f', 3, 64),
+			errstr,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
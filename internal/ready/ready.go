@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package ready checks that the peridot API under test is up and
+// usable before any tests are run against it.
+package ready
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/client"
+)
+
+// Config says where the API is and how long to wait for it.
+type Config struct {
+	// Root is the root URL of the API.
+	Root string
+
+	// Admin is the identity used for the checks that need admin
+	// access.
+	Admin client.Identity
+
+	// Timeout is how long to keep polling /hello before giving up.
+	Timeout time.Duration
+
+	// Backoff is the delay after the first failed poll. It doubles
+	// after each further failure, up to MaxBackoff, unless that is
+	// not positive. The delay is never less than Backoff.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+// Error reports which readiness check did not pass.
+type Error struct {
+	// Check is the name of the check that failed: "hello",
+	// "admin-token" or "resetDB".
+	Check string
+
+	// Detail describes what the check needed.
+	Detail string
+
+	// Err is the last error seen by the check.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("readiness check %q failed: %s: %v", e.Check, e.Detail, e.Err)
+}
+
+// Wait polls /hello until the API answers as expected, then checks
+// that the admin identity is accepted and that the database can be
+// reset. It returns an *Error for the first check that fails.
+func Wait(cfg *Config) error {
+	c := client.New(cfg.Root, cfg.Admin)
+	// don't let a hung connection outlast the whole timeout
+	c.HTTPClient = &http.Client{Timeout: cfg.Timeout}
+
+	err := waitForHello(c, cfg)
+	if err != nil {
+		return &Error{
+			Check:  "hello",
+			Detail: fmt.Sprintf(`GET %s/hello did not return {"message":"hello"} within %v`, cfg.Root, cfg.Timeout),
+			Err:    err,
+		}
+	}
+
+	_, err = c.ListUsers()
+	if err != nil {
+		detail := "GET /users with the admin token did not succeed"
+		switch client.StatusCode(err) {
+		case http.StatusUnauthorized:
+			detail += "; the token was not accepted, so check that the API's JWTSECRETKEY matches the harness"
+		case http.StatusForbidden:
+			detail += "; the token was accepted but is not an admin's, so check the API's INITIALADMINGITHUB"
+		}
+		return &Error{Check: "admin-token", Detail: detail, Err: err}
+	}
+
+	err = c.ResetDB()
+	if err != nil {
+		return &Error{
+			Check:  "resetDB",
+			Detail: "the resetDB admin command did not succeed; the API may not be able to reach its database",
+			Err:    err,
+		}
+	}
+
+	return nil
+}
+
+// waitForHello polls /hello with exponential backoff until it
+// returns the expected message or the timeout passes. It returns
+// the last error seen.
+func waitForHello(c *client.Client, cfg *Config) error {
+	deadline := time.Now().Add(cfg.Timeout)
+	base := cfg.Backoff
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	delay := base
+	for {
+		msg, err := c.Hello()
+		if err == nil && msg != "hello" {
+			err = fmt.Errorf("got message %q", msg)
+		}
+		if err == nil {
+			return nil
+		}
+
+		if time.Now().Add(delay).After(deadline) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+		if cfg.MaxBackoff > 0 && delay > cfg.MaxBackoff {
+			delay = cfg.MaxBackoff
+		}
+		if delay < base {
+			delay = base
+		}
+	}
+}
@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package spdx
+
+import (
+	"encoding/json"
+)
+
+// jsonDocument mirrors the SPDX 2.2 JSON schema, for the fields
+// that Document holds.
+type jsonDocument struct {
+	SPDXVersion  string `json:"spdxVersion"`
+	DataLicense  string `json:"dataLicense"`
+	SPDXID       string `json:"SPDXID"`
+	Name         string `json:"name"`
+	Namespace    string `json:"documentNamespace"`
+	CreationInfo struct {
+		Creators []string `json:"creators"`
+		Created  string   `json:"created"`
+	} `json:"creationInfo"`
+	Packages []struct {
+		Name             string `json:"name"`
+		SPDXID           string `json:"SPDXID"`
+		DownloadLocation string `json:"downloadLocation"`
+		FilesAnalyzed    *bool  `json:"filesAnalyzed"`
+		VerificationCode *struct {
+			Value    string   `json:"packageVerificationCodeValue"`
+			Excludes []string `json:"packageVerificationCodeExcludedFiles"`
+		} `json:"packageVerificationCode"`
+		LicenseConcluded     string   `json:"licenseConcluded"`
+		LicenseInfoFromFiles []string `json:"licenseInfoFromFiles"`
+		LicenseDeclared      string   `json:"licenseDeclared"`
+		CopyrightText        string   `json:"copyrightText"`
+		HasFiles             []string `json:"hasFiles"`
+	} `json:"packages"`
+	Files []struct {
+		FileName  string `json:"fileName"`
+		SPDXID    string `json:"SPDXID"`
+		Checksums []struct {
+			Algorithm string `json:"algorithm"`
+			Value     string `json:"checksumValue"`
+		} `json:"checksums"`
+		LicenseConcluded   string   `json:"licenseConcluded"`
+		LicenseInfoInFiles []string `json:"licenseInfoInFiles"`
+		CopyrightText      string   `json:"copyrightText"`
+	} `json:"files"`
+	Relationships []struct {
+		Element string `json:"spdxElementId"`
+		Type    string `json:"relationshipType"`
+		Related string `json:"relatedSpdxElement"`
+	} `json:"relationships"`
+}
+
+// ParseJSON parses an SPDX document in JSON format.
+func ParseJSON(b []byte) (*Document, error) {
+	var jd jsonDocument
+	err := json.Unmarshal(b, &jd)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Document{
+		SPDXVersion: jd.SPDXVersion,
+		DataLicense: jd.DataLicense,
+		SPDXID:      jd.SPDXID,
+		Name:        jd.Name,
+		Namespace:   jd.Namespace,
+		Creators:    jd.CreationInfo.Creators,
+		Created:     jd.CreationInfo.Created,
+	}
+
+	for _, jp := range jd.Packages {
+		p := &Package{
+			Name:                 jp.Name,
+			SPDXID:               jp.SPDXID,
+			DownloadLocation:     jp.DownloadLocation,
+			FilesAnalyzed:        jp.FilesAnalyzed == nil || *jp.FilesAnalyzed,
+			LicenseConcluded:     jp.LicenseConcluded,
+			LicenseInfoFromFiles: jp.LicenseInfoFromFiles,
+			LicenseDeclared:      jp.LicenseDeclared,
+			CopyrightText:        jp.CopyrightText,
+			FileIDs:              append([]string{}, jp.HasFiles...),
+		}
+		if jp.VerificationCode != nil {
+			p.VerificationCode = jp.VerificationCode.Value
+			p.VerificationCodeExcludes = jp.VerificationCode.Excludes
+		}
+		d.Packages = append(d.Packages, p)
+	}
+
+	for _, jf := range jd.Files {
+		f := &File{
+			Name:              jf.FileName,
+			SPDXID:            jf.SPDXID,
+			Checksums:         map[string]string{},
+			LicenseConcluded:  jf.LicenseConcluded,
+			LicenseInfoInFile: jf.LicenseInfoInFiles,
+			CopyrightText:     jf.CopyrightText,
+		}
+		for _, c := range jf.Checksums {
+			f.Checksums[c.Algorithm] = c.Value
+		}
+		d.Files = append(d.Files, f)
+	}
+
+	for _, jr := range jd.Relationships {
+		d.Relationships = append(d.Relationships, &Relationship{jr.Element, jr.Type, jr.Related})
+	}
+
+	linkFiles(d)
+	return d, nil
+}
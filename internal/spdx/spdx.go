@@ -0,0 +1,230 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package spdx parses and validates the subset of SPDX 2.x
+// documents that the peridot pipeline produces, in either
+// tag-value or JSON format.
+package spdx
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Document is an SPDX document.
+type Document struct {
+	SPDXVersion   string
+	DataLicense   string
+	SPDXID        string
+	Name          string
+	Namespace     string
+	Creators      []string
+	Created       string
+	Packages      []*Package
+	Files         []*File
+	Relationships []*Relationship
+}
+
+// Package is an SPDX package.
+type Package struct {
+	Name                     string
+	SPDXID                   string
+	DownloadLocation         string
+	FilesAnalyzed            bool
+	VerificationCode         string
+	VerificationCodeExcludes []string
+	LicenseConcluded         string
+	LicenseInfoFromFiles     []string
+	LicenseDeclared          string
+	CopyrightText            string
+
+	// FileIDs are the SPDX IDs of the files in this package.
+	FileIDs []string
+}
+
+// File is an SPDX file.
+type File struct {
+	Name              string
+	SPDXID            string
+	Checksums         map[string]string
+	LicenseConcluded  string
+	LicenseInfoInFile []string
+	CopyrightText     string
+}
+
+// Relationship is an SPDX relationship between two elements.
+type Relationship struct {
+	Element string
+	Type    string
+	Related string
+}
+
+// Parse parses an SPDX document in either JSON or tag-value
+// format, deciding which from the content.
+func Parse(b []byte) (*Document, error) {
+	if len(bytes.TrimSpace(b)) > 0 && bytes.TrimSpace(b)[0] == '{' {
+		return ParseJSON(b)
+	}
+	return ParseTagValue(b)
+}
+
+// FileByID returns the file with the given SPDX ID, or nil.
+func (d *Document) FileByID(id string) *File {
+	for _, f := range d.Files {
+		if f.SPDXID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+// PackageFiles returns the files in the given package.
+func (d *Document) PackageFiles(p *Package) []*File {
+	files := []*File{}
+	for _, id := range p.FileIDs {
+		if f := d.FileByID(id); f != nil {
+			files = append(files, f)
+		}
+	}
+	return files
+}
+
+// VerificationCode computes an SPDX package verification code
+// from the SHA1 checksums of the package's files: the SHA1 of the
+// sorted, concatenated, lowercase hex checksums.
+func VerificationCode(sha1s []string) string {
+	sorted := make([]string, len(sha1s))
+	for i, s := range sha1s {
+		sorted[i] = strings.ToLower(s)
+	}
+	sort.Strings(sorted)
+	return fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join(sorted, ""))))
+}
+
+// Validate checks that the document has the required fields, that
+// its IDs are unique and resolvable, and that its checksums and
+// verification codes are well-formed and consistent. It returns
+// every problem found, or nil if there are none.
+func Validate(d *Document) []error {
+	errs := []error{}
+	addErr := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if !strings.HasPrefix(d.SPDXVersion, "SPDX-2.") {
+		addErr("unsupported SPDX version %q", d.SPDXVersion)
+	}
+	if d.DataLicense != "CC0-1.0" {
+		addErr("data license must be CC0-1.0, got %q", d.DataLicense)
+	}
+	if d.SPDXID != "SPDXRef-DOCUMENT" {
+		addErr("document SPDX ID must be SPDXRef-DOCUMENT, got %q", d.SPDXID)
+	}
+	if d.Name == "" {
+		addErr("document has no name")
+	}
+	if d.Namespace == "" {
+		addErr("document has no namespace")
+	}
+	if len(d.Creators) == 0 {
+		addErr("document has no creators")
+	}
+	if d.Created == "" {
+		addErr("document has no creation time")
+	}
+
+	ids := map[string]bool{d.SPDXID: true}
+	checkID := func(kind string, name string, id string) {
+		if !strings.HasPrefix(id, "SPDXRef-") {
+			addErr("%s %q has invalid SPDX ID %q", kind, name, id)
+		}
+		if ids[id] {
+			addErr("%s %q has duplicate SPDX ID %q", kind, name, id)
+		}
+		ids[id] = true
+	}
+
+	for _, f := range d.Files {
+		checkID("file", f.Name, f.SPDXID)
+		sum, ok := f.Checksums["SHA1"]
+		if !ok {
+			addErr("file %q has no SHA1 checksum", f.Name)
+		} else if !isHex(sum, 40) {
+			addErr("file %q has malformed SHA1 checksum %q", f.Name, sum)
+		}
+		if f.LicenseConcluded == "" {
+			addErr("file %q has no concluded license", f.Name)
+		}
+		if len(f.LicenseInfoInFile) == 0 {
+			addErr("file %q has no license information", f.Name)
+		}
+	}
+
+	if len(d.Packages) == 0 {
+		addErr("document has no packages")
+	}
+	for _, p := range d.Packages {
+		checkID("package", p.Name, p.SPDXID)
+		if p.Name == "" {
+			addErr("package %q has no name", p.SPDXID)
+		}
+		if p.DownloadLocation == "" {
+			addErr("package %q has no download location", p.Name)
+		}
+		if !p.FilesAnalyzed {
+			continue
+		}
+
+		if !isHex(p.VerificationCode, 40) {
+			addErr("package %q has malformed verification code %q", p.Name, p.VerificationCode)
+			continue
+		}
+		excluded := map[string]bool{}
+		for _, e := range p.VerificationCodeExcludes {
+			excluded[e] = true
+		}
+		sha1s := []string{}
+		for _, id := range p.FileIDs {
+			f := d.FileByID(id)
+			if f == nil {
+				addErr("package %q refers to unknown file %q", p.Name, id)
+				continue
+			}
+			if !excluded[f.Name] {
+				sha1s = append(sha1s, f.Checksums["SHA1"])
+			}
+		}
+		if want := VerificationCode(sha1s); p.VerificationCode != want {
+			addErr("package %q has verification code %s, but its files give %s", p.Name, p.VerificationCode, want)
+		}
+	}
+
+	for _, r := range d.Relationships {
+		if !ids[r.Element] {
+			addErr("relationship %s %s %s refers to unknown element %q", r.Element, r.Type, r.Related, r.Element)
+		}
+		if !ids[r.Related] && r.Related != "NONE" && r.Related != "NOASSERTION" && !strings.HasPrefix(r.Related, "DocumentRef-") {
+			addErr("relationship %s %s %s refers to unknown element %q", r.Element, r.Type, r.Related, r.Related)
+		}
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// isHex checks whether s is a lowercase hex string of length n.
+func isHex(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
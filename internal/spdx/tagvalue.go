@@ -0,0 +1,202 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package spdx
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// ParseTagValue parses an SPDX document in tag-value format.
+// Files that follow a package are treated as part of it.
+func ParseTagValue(b []byte) (*Document, error) {
+	d := &Document{}
+	var pkg *Package
+	var file *File
+
+	pairs, err := readTagValues(b)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tv := range pairs {
+		tag, value := tv[0], tv[1]
+		switch tag {
+		// document creation information
+		case "SPDXVersion":
+			d.SPDXVersion = value
+		case "DataLicense":
+			d.DataLicense = value
+		case "DocumentName":
+			d.Name = value
+		case "DocumentNamespace":
+			d.Namespace = value
+		case "Creator":
+			d.Creators = append(d.Creators, value)
+		case "Created":
+			d.Created = value
+		case "SPDXID":
+			switch {
+			case file != nil:
+				file.SPDXID = value
+			case pkg != nil:
+				pkg.SPDXID = value
+			default:
+				d.SPDXID = value
+			}
+
+		// package information
+		case "PackageName":
+			pkg = &Package{Name: value, FilesAnalyzed: true}
+			file = nil
+			d.Packages = append(d.Packages, pkg)
+		case "PackageDownloadLocation", "FilesAnalyzed", "PackageVerificationCode",
+			"PackageLicenseConcluded", "PackageLicenseInfoFromFiles",
+			"PackageLicenseDeclared", "PackageCopyrightText":
+			if pkg == nil {
+				return nil, fmt.Errorf("%s found before any PackageName", tag)
+			}
+			setPackageTag(pkg, tag, value)
+
+		// file information
+		case "FileName":
+			file = &File{Name: value, Checksums: map[string]string{}}
+			d.Files = append(d.Files, file)
+			if pkg != nil {
+				pkg.FileIDs = append(pkg.FileIDs, "")
+			}
+		case "FileChecksum", "LicenseConcluded", "LicenseInfoInFile", "FileCopyrightText":
+			if file == nil {
+				return nil, fmt.Errorf("%s found before any FileName", tag)
+			}
+			err = setFileTag(file, tag, value)
+			if err != nil {
+				return nil, err
+			}
+
+		case "Relationship":
+			fields := strings.Fields(value)
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("invalid relationship %q", value)
+			}
+			d.Relationships = append(d.Relationships, &Relationship{fields[0], fields[1], fields[2]})
+		}
+
+		// fill in the file's ID in its package once it is known
+		if tag == "SPDXID" && file != nil && pkg != nil {
+			pkg.FileIDs[len(pkg.FileIDs)-1] = value
+		}
+	}
+
+	linkFiles(d)
+	return d, nil
+}
+
+// readTagValues splits tag-value content into tag and value pairs,
+// joining multi-line values wrapped in <text>...</text>.
+func readTagValues(b []byte) ([][2]string, error) {
+	pairs := [][2]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		i := strings.Index(line, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("line %d: expected tag: value, got %q", lineNum, line)
+		}
+		tag := strings.TrimSpace(line[:i])
+		value := strings.TrimSpace(line[i+1:])
+
+		if strings.HasPrefix(value, "<text>") {
+			text := strings.TrimPrefix(value, "<text>")
+			for !strings.Contains(text, "</text>") {
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("line %d: unterminated <text> for %s", lineNum, tag)
+				}
+				lineNum++
+				text += "\n" + scanner.Text()
+			}
+			value = text[:strings.Index(text, "</text>")]
+		}
+
+		pairs = append(pairs, [2]string{tag, value})
+	}
+
+	return pairs, scanner.Err()
+}
+
+func setPackageTag(pkg *Package, tag string, value string) {
+	switch tag {
+	case "PackageDownloadLocation":
+		pkg.DownloadLocation = value
+	case "FilesAnalyzed":
+		pkg.FilesAnalyzed = value != "false"
+	case "PackageVerificationCode":
+		// e.g. "d6a7...2758 (excludes: ./package.spdx)"
+		code := value
+		if i := strings.Index(value, "("); i >= 0 {
+			code = strings.TrimSpace(value[:i])
+			excludes := strings.TrimSuffix(strings.TrimSpace(value[i+1:]), ")")
+			excludes = strings.TrimSpace(strings.TrimPrefix(excludes, "excludes:"))
+			for _, e := range strings.Split(excludes, ",") {
+				if e = strings.TrimSpace(e); e != "" {
+					pkg.VerificationCodeExcludes = append(pkg.VerificationCodeExcludes, e)
+				}
+			}
+		}
+		pkg.VerificationCode = code
+	case "PackageLicenseConcluded":
+		pkg.LicenseConcluded = value
+	case "PackageLicenseInfoFromFiles":
+		pkg.LicenseInfoFromFiles = append(pkg.LicenseInfoFromFiles, value)
+	case "PackageLicenseDeclared":
+		pkg.LicenseDeclared = value
+	case "PackageCopyrightText":
+		pkg.CopyrightText = value
+	}
+}
+
+func setFileTag(file *File, tag string, value string) error {
+	switch tag {
+	case "FileChecksum":
+		// e.g. "SHA1: 85ed0817af83a24ad8da68c2b5094de69833983c"
+		i := strings.Index(value, ":")
+		if i < 0 {
+			return fmt.Errorf("invalid checksum %q for file %s", value, file.Name)
+		}
+		file.Checksums[strings.TrimSpace(value[:i])] = strings.TrimSpace(value[i+1:])
+	case "LicenseConcluded":
+		file.LicenseConcluded = value
+	case "LicenseInfoInFile":
+		file.LicenseInfoInFile = append(file.LicenseInfoInFile, value)
+	case "FileCopyrightText":
+		file.CopyrightText = value
+	}
+	return nil
+}
+
+// linkFiles adds files that a package CONTAINS, according to the
+// document's relationships, to the package's file IDs.
+func linkFiles(d *Document) {
+	for _, p := range d.Packages {
+		has := map[string]bool{}
+		for _, id := range p.FileIDs {
+			has[id] = true
+		}
+		for _, r := range d.Relationships {
+			if r.Element == p.SPDXID && r.Type == "CONTAINS" && d.FileByID(r.Related) != nil && !has[r.Related] {
+				p.FileIDs = append(p.FileIDs, r.Related)
+				has[r.Related] = true
+			}
+		}
+	}
+}
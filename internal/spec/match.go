@@ -0,0 +1,269 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// matcher is a check on one value in a response. In a spec, a
+// matcher is either a plain value, which the response value must
+// equal, or a mapping of one or more of:
+//
+//	equals: <value>      the value must equal this
+//	exists: true|false   the path must (or must not) be present
+//	type: <type>         string, number, bool, array, object or null
+//	length: <n>          a string, array or object of this length
+//	contains: <value>    a string containing this substring, or an
+//	                     array containing this element
+//	regex: <pattern>     a string matching this regular expression
+//
+// To check that a value equals an object, use equals.
+type matcher struct {
+	equals      interface{}
+	hasEquals   bool
+	exists      *bool
+	typ         string
+	length      *int
+	contains    interface{}
+	hasContains bool
+	regex       *regexp.Regexp
+}
+
+// jsonTypes are the names accepted by the type matcher.
+var jsonTypes = map[string]bool{
+	"string": true,
+	"number": true,
+	"bool":   true,
+	"array":  true,
+	"object": true,
+	"null":   true,
+}
+
+// newMatcher parses a matcher from its spec form.
+func newMatcher(spec interface{}) (*matcher, error) {
+	ops, ok := normalize(spec).(map[string]interface{})
+	if !ok {
+		return &matcher{equals: normalize(spec), hasEquals: true}, nil
+	}
+	if len(ops) == 0 {
+		return nil, fmt.Errorf("empty matcher; use equals: {} to match an empty object")
+	}
+
+	m := &matcher{}
+	for _, op := range sortedKeys(ops) {
+		arg := ops[op]
+		switch op {
+		case "equals":
+			m.equals = arg
+			m.hasEquals = true
+		case "exists":
+			b, ok := arg.(bool)
+			if !ok {
+				return nil, fmt.Errorf("exists must be true or false")
+			}
+			m.exists = &b
+		case "type":
+			s, ok := arg.(string)
+			if !ok || !jsonTypes[s] {
+				return nil, fmt.Errorf("type must be one of string, number, bool, array, object or null")
+			}
+			m.typ = s
+		case "length":
+			f, ok := arg.(float64)
+			if !ok || f < 0 || f != float64(int(f)) {
+				return nil, fmt.Errorf("length must be a non-negative integer")
+			}
+			n := int(f)
+			m.length = &n
+		case "contains":
+			m.contains = arg
+			m.hasContains = true
+		case "regex":
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("regex must be a string")
+			}
+			re, err := regexp.Compile(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid regex: %v", err)
+			}
+			m.regex = re
+		default:
+			return nil, fmt.Errorf("unknown matcher %q; use equals: to match an object", op)
+		}
+	}
+
+	return m, nil
+}
+
+// check returns an error describing how a value fails the matcher,
+// or nil if it passes. found reports whether the value's path was
+// present in the response.
+func (m *matcher) check(v interface{}, found bool) error {
+	if m.exists != nil {
+		if *m.exists != found {
+			if found {
+				return fmt.Errorf("expected no value, got %s", show(v))
+			}
+			return fmt.Errorf("expected a value, but not found in response")
+		}
+		if !found {
+			return nil
+		}
+	}
+	if !found {
+		return fmt.Errorf("not found in response")
+	}
+
+	if m.hasEquals && !reflect.DeepEqual(m.equals, v) {
+		return fmt.Errorf("expected %s, got %s", show(m.equals), show(v))
+	}
+
+	if m.typ != "" && jsonType(v) != m.typ {
+		return fmt.Errorf("expected %s, got %s %s", m.typ, jsonType(v), show(v))
+	}
+
+	if m.length != nil {
+		var n int
+		switch t := v.(type) {
+		case string:
+			n = utf8.RuneCountInString(t)
+		case []interface{}:
+			n = len(t)
+		case map[string]interface{}:
+			n = len(t)
+		default:
+			return fmt.Errorf("expected length %d, got %s %s", *m.length, jsonType(v), show(v))
+		}
+		if n != *m.length {
+			return fmt.Errorf("expected length %d, got length %d", *m.length, n)
+		}
+	}
+
+	if m.hasContains {
+		switch t := v.(type) {
+		case string:
+			sub, ok := m.contains.(string)
+			if !ok || !strings.Contains(t, sub) {
+				return fmt.Errorf("expected to contain %s, got %s", show(m.contains), show(v))
+			}
+		case []interface{}:
+			in := false
+			for _, e := range t {
+				if reflect.DeepEqual(e, m.contains) {
+					in = true
+					break
+				}
+			}
+			if !in {
+				return fmt.Errorf("expected to contain %s, got %s", show(m.contains), show(v))
+			}
+		default:
+			return fmt.Errorf("expected a string or array containing %s, got %s", show(m.contains), show(v))
+		}
+	}
+
+	if m.regex != nil {
+		s, ok := v.(string)
+		if !ok || !m.regex.MatchString(s) {
+			return fmt.Errorf("expected to match /%s/, got %s", m.regex, show(v))
+		}
+	}
+
+	return nil
+}
+
+// lookup finds the value at a dotted path, such as
+// "projects.0.name", in a decoded JSON document.
+func lookup(doc interface{}, path string) (interface{}, bool) {
+	v := doc
+	for _, key := range strings.Split(path, ".") {
+		switch t := v.(type) {
+		case map[string]interface{}:
+			e, ok := t[key]
+			if !ok {
+				return nil, false
+			}
+			v = e
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(t) {
+				return nil, false
+			}
+			v = t[i]
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// normalize converts a value decoded from YAML into the types that
+// encoding/json decodes to, so that the two can be compared and
+// the value can be marshalled.
+func normalize(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			out[k] = normalize(e)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			out[i] = normalize(e)
+		}
+		return out
+	case int:
+		return float64(t)
+	case int64:
+		return float64(t)
+	case uint64:
+		return float64(t)
+	default:
+		return v
+	}
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// show formats a value for an error message.
+func show(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
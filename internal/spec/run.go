@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// varRef matches a reference to a captured variable.
+var varRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// caseTest returns the test for a case. Each request, and each
+// check on its response, is numbered as a step of the test.
+func (f *File) caseTest(c *Case) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   f.Suite,
+			Element: f.Element,
+			ID:      c.ID,
+			Tags:    append(append([]string{}, f.Tags...), c.Tags...),
+		}
+
+		vars := map[string]interface{}{}
+		n := 0
+		step := func() string {
+			n++
+			return strconv.Itoa(n)
+		}
+
+		for _, s := range c.Steps {
+			if !runStep(res, root, s, firstOf(s.As, c.As, f.As, "none"), vars, step) {
+				return res
+			}
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// runStep sends one step's request and checks its response,
+// saving any captured values in vars. It returns false if the
+// test has failed.
+func runStep(res *testresult.TestResult, root string, s *Step, as string, vars map[string]interface{}, step func() string) bool {
+	// send the request
+	st := step()
+	path, err := expandString(s.Path, vars)
+	if err != nil {
+		utils.FailTest(res, st, err)
+		return false
+	}
+	body, err := requestBody(s.Body, vars)
+	if err != nil {
+		utils.FailTest(res, st, err)
+		return false
+	}
+
+	code, got, err := utils.Send(res, st, s.Method, root+path, body, as)
+	res.Got = got
+	if err != nil {
+		utils.FailTest(res, st, err)
+		return false
+	}
+	if code != s.Status {
+		utils.FailTest(res, st, fmt.Errorf("expected HTTP status code %d, got %d", s.Status, code))
+		return false
+	}
+
+	// compare the whole body
+	if s.JSON != nil {
+		st = step()
+		want, err := expand(normalize(s.JSON), vars)
+		if err != nil {
+			utils.FailTest(res, st, err)
+			return false
+		}
+		b, err := json.Marshal(want)
+		if err != nil {
+			utils.FailTest(res, st, err)
+			return false
+		}
+		res.Wanted = string(b)
+		if !utils.IsMatch(res) {
+			utils.FailMatch(res, st)
+			return false
+		}
+	}
+
+	if len(s.Match) == 0 && len(s.Capture) == 0 {
+		return true
+	}
+
+	var doc interface{}
+	err = json.Unmarshal(got, &doc)
+	if err != nil {
+		utils.FailTest(res, step(), fmt.Errorf("response is not JSON: %v", err))
+		return false
+	}
+
+	// check the matchers, in path order so that failures are
+	// reported consistently
+	for _, path := range sortedKeys(s.Match) {
+		st = step()
+		want, err := expand(normalize(s.Match[path]), vars)
+		if err != nil {
+			utils.FailTest(res, st, err)
+			return false
+		}
+		m, err := newMatcher(want)
+		if err != nil {
+			utils.FailTest(res, st, err)
+			return false
+		}
+		v, found := lookup(doc, path)
+		err = m.check(v, found)
+		if err != nil {
+			utils.FailTest(res, st, fmt.Errorf("%s: %v", path, err))
+			return false
+		}
+	}
+
+	// and save the captured values
+	for name, path := range s.Capture {
+		v, found := lookup(doc, path)
+		if !found {
+			utils.FailTest(res, step(), fmt.Errorf("cannot capture %s: %s not found in response", name, path))
+			return false
+		}
+		vars[name] = v
+	}
+
+	return true
+}
+
+// requestBody returns the body to send for a step: a string body
+// with its variables expanded, or any other value as JSON.
+func requestBody(body interface{}, vars map[string]interface{}) (string, error) {
+	if body == nil {
+		return "", nil
+	}
+	if s, ok := body.(string); ok {
+		return expandString(s, vars)
+	}
+
+	v, err := expand(normalize(body), vars)
+	if err != nil {
+		return "", err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// expandString replaces the variable references in a string with
+// their values. Strings are inserted as they are, and other values
+// as JSON.
+func expandString(s string, vars map[string]interface{}) (string, error) {
+	var err error
+	out := varRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := varRef.FindStringSubmatch(ref)[1]
+		v, ok := vars[name]
+		if !ok {
+			err = fmt.Errorf("variable %s has not been captured", name)
+			return ref
+		}
+		if str, ok := v.(string); ok {
+			return str
+		}
+		b, _ := json.Marshal(v)
+		return string(b)
+	})
+	return out, err
+}
+
+// expand replaces the variable references in a normalized value.
+// A string that is just a reference is replaced by the variable's
+// value itself, keeping its type, so that e.g. a captured ID stays
+// a number.
+func expand(v interface{}, vars map[string]interface{}) (interface{}, error) {
+	switch t := v.(type) {
+	case string:
+		if m := varRef.FindStringSubmatch(t); m != nil && m[0] == t {
+			val, ok := vars[m[1]]
+			if !ok {
+				return nil, fmt.Errorf("variable %s has not been captured", m[1])
+			}
+			return val, nil
+		}
+		return expandString(t, vars)
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, e := range t {
+			x, err := expand(e, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = x
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, e := range t {
+			x, err := expand(e, vars)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = x
+		}
+		return out, nil
+	default:
+		return v, nil
+	}
+}
+
+// firstOf returns the first non-empty string.
+func firstOf(ss ...string) string {
+	for _, s := range ss {
+		if s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// sortedKeys returns a map's keys in order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
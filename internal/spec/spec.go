@@ -0,0 +1,236 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package spec reads declarative test cases from YAML files and
+// turns them into testresult.TestFuncs, so that tests can be added
+// without writing Go.
+//
+// A spec file looks like:
+//
+//	suite: specs
+//	element: projects
+//	as: operator
+//	cases:
+//	  - id: POST then GET (operator)
+//	    steps:
+//	      - method: POST
+//	        path: /projects
+//	        body: {name: plugh, fullname: The plugh Project}
+//	        status: 201
+//	        capture: {pid: id}
+//	      - method: GET
+//	        path: /projects/${pid}
+//	        as: viewer
+//	        match:
+//	          project.name: plugh
+//	          project.id: {type: number}
+//
+// Each step sends one request and checks its status code, and
+// optionally the whole JSON body ("json") or parts of it
+// ("match"). Values captured from a response can be used in the
+// path, body and expectations of later steps as ${name}.
+package spec
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// File is one spec file: a set of test cases for an element.
+type File struct {
+	// Suite is the suite that the cases report under. Defaults
+	// to "specs".
+	Suite string `yaml:"suite"`
+
+	// Element is the element that the cases report under.
+	Element string `yaml:"element"`
+
+	// As is the default user for the cases' steps.
+	As string `yaml:"as"`
+
+	// Tags label all of the cases, as well as their own tags.
+	Tags []string `yaml:"tags"`
+
+	// Cases are the test cases, run in order.
+	Cases []*Case `yaml:"cases"`
+}
+
+// Case is one test case: a sequence of steps that must all pass.
+type Case struct {
+	// ID identifies the case within the element.
+	ID string `yaml:"id"`
+
+	// As is the default user for the case's steps, overriding
+	// the file's.
+	As string `yaml:"as"`
+
+	// Tags label the case, e.g. "slow".
+	Tags []string `yaml:"tags"`
+
+	// Steps are the requests to send, in order.
+	Steps []*Step `yaml:"steps"`
+}
+
+// Step is one request and the checks on its response.
+type Step struct {
+	// Method is the HTTP method, e.g. "GET".
+	Method string `yaml:"method"`
+
+	// Path is the request path, relative to the root URL.
+	Path string `yaml:"path"`
+
+	// As is the user to send the request as, overriding the
+	// case's. "none" sends no authorization.
+	As string `yaml:"as"`
+
+	// Body is the request body: either a string sent as it is,
+	// or a YAML value sent as JSON.
+	Body interface{} `yaml:"body"`
+
+	// Status is the expected HTTP status code. Defaults to 200.
+	Status int `yaml:"status"`
+
+	// JSON, if set, is the expected response body, compared as
+	// JSON.
+	JSON interface{} `yaml:"json"`
+
+	// Match maps paths within the response, such as
+	// "projects.0.name", to matchers that the value there must
+	// satisfy. See matcher for the forms a matcher can take.
+	Match map[string]interface{} `yaml:"match"`
+
+	// Capture maps variable names to paths within the response
+	// whose values are saved for later steps.
+	Capture map[string]string `yaml:"capture"`
+}
+
+// Parse parses a spec file's contents. The name is used in error
+// messages.
+func Parse(name string, b []byte) (*File, error) {
+	f := &File{}
+	err := yaml.UnmarshalStrict(b, f)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", name, err)
+	}
+
+	if f.Suite == "" {
+		f.Suite = "specs"
+	}
+	err = f.validate()
+	if err != nil {
+		return nil, fmt.Errorf("error in %s: %v", name, err)
+	}
+
+	return f, nil
+}
+
+// Load reads every .yaml and .yml file in a directory, in name
+// order, and returns the tests for all of their cases.
+func Load(dir string) ([]testresult.TestFunc, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, fi := range files {
+		ext := filepath.Ext(fi.Name())
+		if !fi.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, filepath.Join(dir, fi.Name()))
+		}
+	}
+	sort.Strings(names)
+
+	allTests := []testresult.TestFunc{}
+	for _, name := range names {
+		b, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := Parse(name, b)
+		if err != nil {
+			return nil, err
+		}
+		allTests = append(allTests, f.Tests()...)
+	}
+
+	return allTests, nil
+}
+
+// Tests returns a test for each of the file's cases.
+func (f *File) Tests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+	for _, c := range f.Cases {
+		allTests = append(allTests, f.caseTest(c))
+	}
+	return allTests
+}
+
+// validate checks that the file is complete and that its matchers
+// are well-formed, so that mistakes are reported when the file is
+// loaded rather than as test failures.
+func (f *File) validate() error {
+	if f.Element == "" {
+		return fmt.Errorf("missing element")
+	}
+	if len(f.Cases) == 0 {
+		return fmt.Errorf("no cases")
+	}
+
+	ids := map[string]bool{}
+	for i, c := range f.Cases {
+		if c.ID == "" {
+			return fmt.Errorf("case %d: missing id", i+1)
+		}
+		if ids[c.ID] {
+			return fmt.Errorf("case %q: duplicate id", c.ID)
+		}
+		ids[c.ID] = true
+
+		if len(c.Steps) == 0 {
+			return fmt.Errorf("case %q: no steps", c.ID)
+		}
+		for j, s := range c.Steps {
+			err := s.validate()
+			if err != nil {
+				return fmt.Errorf("case %q, step %d: %v", c.ID, j+1, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validate checks a single step.
+func (s *Step) validate() error {
+	if s.Method == "" {
+		return fmt.Errorf("missing method")
+	}
+	s.Method = strings.ToUpper(s.Method)
+	if !strings.HasPrefix(s.Path, "/") {
+		return fmt.Errorf("path %q must start with /", s.Path)
+	}
+	if s.Status == 0 {
+		s.Status = 200
+	}
+
+	for path, m := range s.Match {
+		_, err := newMatcher(m)
+		if err != nil {
+			return fmt.Errorf("match %s: %v", path, err)
+		}
+	}
+	for name, path := range s.Capture {
+		if name == "" || path == "" {
+			return fmt.Errorf("capture needs a name and a path")
+		}
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@
 
 package testresult
 
+import "time"
+
 // TestResult contains data on the test, identifying it
 // and whether it succeeded or failed.
 type TestResult struct {
@@ -30,6 +32,109 @@ type TestResult struct {
 
 	// Got holds the latest JSON byte slice that was received.
 	Got []byte
+
+	// Tags label the test, e.g. "slow", "destructive" or "agent",
+	// so that it can be selected or excluded by tag.
+	Tags []string
+
+	// Skipped indicates that the test was not run, or stopped
+	// without passing or failing.
+	Skipped bool
+
+	// XFail indicates that the test is expected to fail, because
+	// of a known bug in the API.
+	XFail bool
+
+	// Reason explains why the test was skipped or is expected to
+	// fail.
+	Reason string
+
+	// Issue links to the bug report behind a skip or expected
+	// failure, if any.
+	Issue string
+
+	// Duration is how long the test took to run, as measured by
+	// the runner.
+	Duration time.Duration
+
+	// Transcript records the requests that the test made and
+	// their responses, in order.
+	Transcript []*Exchange
+}
+
+// Exchange is one request that a test made, and its response.
+type Exchange struct {
+	// Step is the test step that made the request.
+	Step string
+
+	// Method, URL and User describe the request, with User being
+	// the test user it was sent as.
+	Method string
+	URL    string
+	User   string
+
+	// RequestBody is the body that was sent, if any.
+	RequestBody string
+
+	// Status is the response's HTTP status code, or 0 if there
+	// was no response.
+	Status int
+
+	// ResponseBody is the body that was received. Truncated is
+	// set if only the start of it was kept.
+	ResponseBody []byte
+	Truncated    bool
+
+	// Error is why there was no response, if there wasn't.
+	Error string
+}
+
+// Outcome is the overall result of a test.
+type Outcome string
+
+// The possible outcomes of a test.
+const (
+	// Pass means the test passed.
+	Pass Outcome = "pass"
+
+	// Fail means the test failed.
+	Fail Outcome = "fail"
+
+	// Skip means the test was skipped.
+	Skip Outcome = "skip"
+
+	// XFail means the test failed, as expected.
+	XFail Outcome = "xfail"
+
+	// XPass means the test passed, but was expected to fail;
+	// the bug it was marked with may have been fixed.
+	XPass Outcome = "xpass"
+)
+
+// Outcome returns the test's outcome.
+func (r *TestResult) Outcome() Outcome {
+	switch {
+	case r.Skipped:
+		return Skip
+	case r.XFail && r.Success:
+		return XPass
+	case r.XFail:
+		return XFail
+	case r.Success:
+		return Pass
+	default:
+		return Fail
+	}
+}
+
+// HasTag returns whether the test has the given tag.
+func (r *TestResult) HasTag(tag string) bool {
+	for _, t := range r.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
 }
 
 // TestFunc defines a function that takes a string with the
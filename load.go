@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/load"
+)
+
+// runLoad sets up the fixtures once, then drives the request mix
+// against the API and reports on the results. It returns the
+// process exit code.
+func runLoad(root string) int {
+	mix := load.DefaultMix()
+	if *loadMix != "" {
+		var err error
+		mix, err = load.ReadMix(*loadMix)
+		if err != nil {
+			fmt.Printf("Error reading load mix: %v\n", err)
+			return 1
+		}
+	}
+
+	err := fixtures.ResetDB(root)
+	if err != nil {
+		fmt.Printf("Error resetting DB before load run: %v\n", err)
+		return 1
+	}
+	err = fixtures.SetupFixture(root)
+	if err != nil {
+		fmt.Printf("Error setting fixtures before load run: %v\n", err)
+		return 1
+	}
+
+	seed := *loadSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	cfg := &load.Config{
+		Root:        root,
+		Mix:         mix,
+		Concurrency: *loadConcurrency,
+		Rate:        *loadRate,
+		Duration:    *loadDuration,
+		Seed:        seed,
+	}
+	fmt.Printf("Load testing for %v with %d workers", cfg.Duration, cfg.Concurrency)
+	if cfg.Rate > 0 {
+		fmt.Printf(" at %.1f req/s", cfg.Rate)
+	}
+	fmt.Printf(", with seed %d (give -load-seed=%d to reproduce)\n\n", cfg.Seed, cfg.Seed)
+
+	start := time.Now()
+	samples, err := load.Run(cfg)
+	if err != nil {
+		fmt.Printf("Error running load: %v\n", err)
+		return 1
+	}
+	elapsed := time.Since(start)
+
+	load.PrintSummary(os.Stdout, load.Summarize(samples, elapsed), elapsed)
+
+	if *loadCSV != "" {
+		err = load.WriteCSV(*loadCSV, samples)
+		if err != nil {
+			fmt.Printf("Error writing samples to %s: %v\n", *loadCSV, err)
+			return 1
+		}
+		fmt.Printf("\nWrote %d samples to %s\n", len(samples), *loadCSV)
+	}
+
+	return 0
+}
@@ -3,81 +3,340 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"reflect"
 	"runtime"
-	"text/tabwriter"
+	"time"
 
 	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/dbinspect"
+	"github.com/swinslow/peridot-api-testing/internal/htmlreport"
+	"github.com/swinslow/peridot-api-testing/internal/ready"
 	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/agentcaps"
+	"github.com/swinslow/peridot-api-testing/test/concurrency"
+	"github.com/swinslow/peridot-api-testing/test/dbstate"
 	"github.com/swinslow/peridot-api-testing/test/endpoints"
+	"github.com/swinslow/peridot-api-testing/test/errcontract"
+	"github.com/swinslow/peridot-api-testing/test/jobgraph"
+	"github.com/swinslow/peridot-api-testing/test/privesc"
+	"github.com/swinslow/peridot-api-testing/test/protocol"
+	"github.com/swinslow/peridot-api-testing/test/pulls"
+	"github.com/swinslow/peridot-api-testing/test/scale"
+	"github.com/swinslow/peridot-api-testing/test/security"
+	"github.com/swinslow/peridot-api-testing/test/specs"
+	"github.com/swinslow/peridot-api-testing/test/utils"
 )
 
+var (
+	rootURL = flag.String("root", "http://sut:3005", "root URL of the peridot API under test")
+
+	readyTimeout    = flag.Duration("ready-timeout", 30*time.Second, "how long to wait for the API to answer /hello before giving up")
+	readyBackoff    = flag.Duration("ready-backoff", 250*time.Millisecond, "delay after the first failed readiness poll; doubles after each failure")
+	readyMaxBackoff = flag.Duration("ready-max-backoff", 4*time.Second, "longest delay between readiness polls")
+
+	dbDSN       = flag.String("db", "", "Postgres connection string for the API's database, e.g. postgres://postgres-dev@db:5432/dev?sslmode=disable; enables the postgres restore mode and the database state tests")
+	restoreMode = flag.String("restore", fixtures.ModeAuto, "how to restore the fixture before each test: admin, postgres, replay or auto")
+
+	loadMode        = flag.Bool("load", false, "run a load test instead of the test suites")
+	loadDuration    = flag.Duration("load-duration", 30*time.Second, "how long to run the load test")
+	loadConcurrency = flag.Int("load-concurrency", 10, "number of concurrent workers for the load test")
+	loadRate        = flag.Float64("load-rate", 0, "target requests per second for the load test; 0 for as fast as possible")
+	loadMix         = flag.String("load-mix", "", "JSON file with the load test request mix; defaults to the built-in mix")
+	loadCSV         = flag.String("load-csv", "", "file to write raw load test samples to, as CSV")
+	loadSeed        = flag.Int64("load-seed", 0, "seed for choosing requests from the mix, to reproduce an earlier load run; 0 for one based on the time")
+
+	agentCapsExpectations = flag.String("agentcaps-expectations", agentcaps.ExpectationsFile, "JSON file with the expected results for the agent capability tests")
+	protocolPolicy        = flag.String("protocol-policy", protocol.PolicyFile, "JSON file with the expected HTTP protocol behavior for the protocol tests")
+
+	repeat  = flag.Int("repeat", 1, "number of times to run the selected tests; with more than one, pass rates are reported")
+	noReset = flag.Bool("no-reset", false, "restore the fixture only once per round instead of before each test, to expose tests that depend on each other")
+
+	historyDir        = flag.String("history-dir", "", "directory in which to keep each run's results, for the report command; if empty, results are not kept")
+	runID             = flag.String("run-id", "", "ID under which to keep this run's results, e.g. a CI build number; defaults to one based on the start time")
+	sutVersionFlag    = flag.String("sut-version", "", "version of the API under test, to keep with the run's results")
+	sutVersionPath    = flag.String("sut-version-path", "", "path from which to fetch the version of the API under test, if -sut-version is not set; its \"version\" field is used if it is JSON, or else the whole response")
+	reportWindow      = flag.Int("report-window", 30, "number of recent runs that the report command looks at for flaky and slowing tests")
+	reportRows        = flag.Int("report-rows", 25, "most rows the report command shows in each list; 0 for all")
+	reportSlowdown    = flag.Float64("report-slowdown", 1.5, "how many times its earlier median duration a test must take in the latest run to be reported as slowing down")
+	reportMinSlowdown = flag.Duration("report-min-slowdown", 50*time.Millisecond, "smallest increase in a test's duration that is reported as slowing down")
+
+	htmlReport = flag.String("html", "", "file to write a self-contained HTML report of the results to")
+
+	annotationsFile = flag.String("annotations", "test/annotations.json", "JSON file with tags, skips and expected failures to apply to tests")
+	specDir         = flag.String("spec-dir", specs.Dir, "directory of YAML test specs to run alongside the Go tests")
+
+	gitDir        = flag.String("git-dir", "", "directory in which to create local git repositories for the fixture; if set, only the repopull suites that need them are run")
+	gitBase       = flag.String("git-base", "git://test:9418", "address at which the API can reach the local git repositories")
+	gitDaemonPort = flag.Int("git-daemon-port", 9418, "port on which to serve the local git repositories with git daemon; 0 to not serve them")
+	pullTimeout   = flag.Duration("pull-timeout", 60*time.Second, "how long to wait for a repopull to finish")
+	spdxPath      = flag.String("spdx-path", pulls.SPDXPath, "path, formatted with the repopull ID, from which to fetch a repopull's SPDX document")
+
+	scaleSeed         = flag.Int64("scale-seed", scale.Seed, "seed for the objects generated by the scale tests")
+	scaleMaxLatency   = flag.Duration("scale-max-latency", scale.MaxLatency, "longest that a list call may take in the scale tests")
+	scaleMaxItemBytes = flag.Int("scale-max-item-bytes", scale.MaxItemBytes, "largest average size of a list item in the scale tests")
+
+	goldenDir    = flag.String("golden-dir", utils.GoldenDir, "directory holding the golden files with the expected responses")
+	updateGolden = flag.Bool("update-golden", false, "record responses that differ from their golden files, and after the run, ask whether to rewrite those files")
+)
+
+// scaleSizes is set by the -scale-sizes flag.
+var scaleSizes intList
+
+// selectTags and skipTags are set by the -tags and -skip-tags
+// flags.
+var selectTags, skipTags stringList
+
+// shuffle is set by the -shuffle flag.
+var shuffle shuffleFlag
+
+func init() {
+	flag.Var(&shuffle, "shuffle", "run the tests in a random order in each round; give -shuffle=SEED or -shuffle SEED to reproduce an earlier order")
+	flag.Var(&selectTags, "tags", "comma-separated tags; if set, only tests with at least one of them are run")
+	flag.Var(&skipTags, "skip-tags", "comma-separated tags; tests with any of them are not run")
+	flag.Var(&scaleSizes, "scale-sizes", "comma-separated numbers of objects to generate for the scale tests, e.g. 100,1000; if empty, the scale tests are skipped")
+}
+
 func main() {
-	root := "http://sut:3005"
-	anyFailed := false
+	flag.Parse()
+	err := shuffle.takeSeed(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		os.Exit(2)
+	}
+	if flag.Arg(0) == "report" {
+		os.Exit(runReport(flag.Args()[1:]))
+	}
+	if flag.NArg() > 0 {
+		fmt.Printf("Unexpected argument %q; the only command is report\n", flag.Arg(0))
+		flag.Usage()
+		os.Exit(2)
+	}
+	utils.GoldenDir = *goldenDir
+	utils.UpdateGolden = *updateGolden
+
+	err = ready.Wait(&ready.Config{
+		Root:       *rootURL,
+		Admin:      utils.Identity("admin"),
+		Timeout:    *readyTimeout,
+		Backoff:    *readyBackoff,
+		MaxBackoff: *readyMaxBackoff,
+	})
+	if err != nil {
+		fmt.Printf("API under test is not ready: %v\n", err)
+		os.Exit(1)
+	}
+
+	if *loadMode {
+		os.Exit(runLoad(*rootURL))
+	}
+
+	os.Exit(runTests(*rootURL))
+}
 
+// runTests runs the selected test suites and reports the results.
+// It returns the process exit code.
+func runTests(root string) int {
+	start := time.Now()
 	allRs := []*testresult.TestResult{}
 	var rs *testresult.TestResult
 
 	// get all test suites
-	allTests := endpoints.GetTests()
-
-	// and run them, resetting DB each time
-	fmt.Printf("Testing (%d total): \n", len(allTests))
-	for _, t := range allTests {
-		fmt.Printf("  %s\n", runtime.FuncForPC(reflect.ValueOf(t).Pointer()).Name())
-		err := fixtures.ResetDB(root)
+	var allTests []testresult.TestFunc
+	if *gitDir != "" {
+		// the other suites expect the made-up repo addresses and
+		// commits, so only run the suites that need real ones
+		g, err := setupGitRepos()
 		if err != nil {
-			fmt.Printf("Error resetting DB before test: %v\n", err)
-			os.Exit(1)
+			fmt.Printf("Error setting up git repositories: %v\n", err)
+			return 1
 		}
-		err = fixtures.SetupFixture(root)
-		if err != nil {
-			fmt.Printf("Error setting fixtures before test: %v\n", err)
-			os.Exit(1)
+		defer g.StopServing()
+		pulls.Repos = g
+		pulls.Timeout = *pullTimeout
+		pulls.SPDXPath = *spdxPath
+		allTests = pulls.GetTests()
+	} else {
+		if *dbDSN != "" {
+			ins, err := dbinspect.Open(*dbDSN)
+			if err != nil {
+				fmt.Printf("Error connecting to database: %v\n", err)
+				return 1
+			}
+			defer ins.Close()
+			dbstate.DB = ins
 		}
+		allTests = defaultTests()
+	}
+
+	annotations, err := readAnnotations(*annotationsFile)
+	if err != nil {
+		fmt.Printf("Error reading annotations: %v\n", err)
+		return 1
+	}
 
-		rs = t(root)
-		allRs = append(allRs, rs)
+	version := ""
+	if *historyDir != "" || *htmlReport != "" {
+		version = sutVersion(root)
 	}
 
+	// set up the fixture once, and snapshot it if possible
+	restorer, err := fixtures.NewRestorer(root, *restoreMode, *dbDSN)
+	if err != nil {
+		fmt.Printf("Error setting fixtures: %v\n", err)
+		return 1
+	}
+	defer restorer.Close()
+
+	// learn the tests' names and tags up front, if any tests are
+	// to be selected or skipped by them
+	var planned []*testresult.TestResult
+	if len(selectTags) > 0 || len(skipTags) > 0 || anySkipped(annotations) {
+		planned = probeTests(allTests)
+		tests := []testresult.TestFunc{}
+		for i, p := range planned {
+			annotate(p, annotations)
+			if !selected(p, selectTags, skipTags) {
+				continue
+			}
+			if p.Skipped {
+				allRs = append(allRs, p)
+				continue
+			}
+			tests = append(tests, allTests[i])
+		}
+		fmt.Printf("Selected %d of %d tests; %d skipped\n", len(tests)+len(allRs), len(allTests), len(allRs))
+		allTests = tests
+	}
+
+	rounds := *repeat
+	if rounds < 1 {
+		rounds = 1
+	}
+	if shuffle.on {
+		fmt.Printf("Shuffling test order with seed %d\n", shuffle.seed)
+	}
+
+	// and run them, restoring the fixture before each one, or
+	// only at the start of each round if -no-reset is set
+	restoring := "before each test"
+	if *noReset {
+		restoring = "once per round"
+	}
+	fmt.Printf("Testing (%d total, restoring fixture by %s %s): \n", len(allTests), restorer.Mode(), restoring)
+	runs := make([][]*testRun, len(allTests))
+	for round := 0; round < rounds; round++ {
+		if rounds > 1 {
+			fmt.Printf("Round %d of %d:\n", round+1, rounds)
+		}
+		prev := -1
+		for pos, i := range testOrder(len(allTests), round, shuffle.on, shuffle.seed) {
+			t := allTests[i]
+			fmt.Printf("  %s\n", runtime.FuncForPC(reflect.ValueOf(t).Pointer()).Name())
+			if !*noReset || pos == 0 {
+				err := restorer.Restore()
+				if err != nil {
+					fmt.Printf("Error restoring fixture before test: %v\n", err)
+					return 1
+				}
+			}
+
+			begin := time.Now()
+			rs = t(root)
+			rs.Duration = time.Since(begin)
+			utils.CheckGolden(rs)
+			annotate(rs, annotations)
+			runs[i] = append(runs[i], &testRun{res: rs, prev: prev})
+			prev = i
+		}
+	}
+	skipped := allRs
+	allRs = append(allRs, summarizeRuns(runs)...)
+
 	fmt.Printf("\n\n")
 
-	// set up tabwriter for outputting test result table
-	w := tabwriter.NewWriter(os.Stdout, 8, 4, 1, ' ', 0)
+	anyFailed := printResults(allRs)
+
+	fmt.Printf("\nFixture restored %d times by %s in %v", restorer.Restores, restorer.Mode(), restorer.RestoreTime)
+	if restorer.Mode() != fixtures.ModeReplay {
+		fmt.Printf("; about %v saved versus replay", restorer.Saved())
+	}
+	fmt.Printf("\n")
 
-	// output results
-	for _, r := range allRs {
-		var result string
-		if r.Success {
-			result = "ok"
-		} else {
-			result = "FAIL"
+	if *updateGolden {
+		written, err := updateGoldenFiles(os.Stdin)
+		if err != nil {
+			fmt.Printf("Error updating golden files: %v\n", err)
+			return 1
+		}
+		if !written {
 			anyFailed = true
 		}
+	}
+
+	printDetails(allRs)
+
+	if *htmlReport != "" {
+		err := writeHTMLReport(*htmlReport, allRs, &htmlreport.Run{
+			Root:       root,
+			SUTVersion: version,
+			Started:    start,
+			Duration:   time.Since(start).Round(time.Millisecond),
+		})
+		if err != nil {
+			fmt.Printf("Error writing HTML report: %v\n", err)
+			return 1
+		}
+	}
 
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Suite, r.Element, r.ID, result)
+	if *historyDir != "" {
+		err := saveHistory(start, root, version, skipped, runs)
+		if err != nil {
+			fmt.Printf("Error saving results to history: %v\n", err)
+			return 1
+		}
 	}
-	w.Flush()
 
-	if anyFailed {
-		// print details of failing tests
-		fmt.Printf("\n\n==========\n\n")
-		for _, r := range allRs {
-			if !r.Success {
-				fmt.Printf("%s:%s:%s\n", r.Suite, r.Element, r.ID)
-				fmt.Printf("    Status: FAIL\n")
-				fmt.Printf("    Step:   %s\n", r.FailStep)
-				fmt.Printf("    Errors: %v\n", r.FailError)
-				fmt.Printf("    Wanted: %s\n", r.Wanted)
-				fmt.Printf("    Got:    %s\n", r.Got)
-				fmt.Printf("\n==========\n\n")
-			}
+	if rounds > 1 || shuffle.on || *noReset {
+		if printRepeatReport(runs, rounds, &shuffle, *noReset) {
+			anyFailed = true
 		}
+	}
 
+	if anyFailed {
 		// return failure status code
-		os.Exit(1)
+		return 1
 	}
+
+	return 0
+}
+
+// defaultTests returns the test suites that run against the
+// default fixture, as configured by the command line flags.
+func defaultTests() []testresult.TestFunc {
+	allTests := endpoints.GetTests()
+	allTests = append(allTests, concurrency.GetTests()...)
+	allTests = append(allTests, jobgraph.GetTests()...)
+	agentcaps.ExpectationsFile = *agentCapsExpectations
+	allTests = append(allTests, agentcaps.GetTests()...)
+	allTests = append(allTests, security.GetTests()...)
+	allTests = append(allTests, privesc.GetTests()...)
+	protocol.PolicyFile = *protocolPolicy
+	allTests = append(allTests, protocol.GetTests()...)
+	allTests = append(allTests, errcontract.GetTests()...)
+	specs.Dir = *specDir
+	allTests = append(allTests, specs.GetTests()...)
+	if dbstate.DB != nil {
+		allTests = append(allTests, dbstate.GetTests()...)
+	}
+	if len(scaleSizes) > 0 {
+		scale.Sizes = scaleSizes
+		scale.Seed = *scaleSeed
+		scale.MaxLatency = *scaleMaxLatency
+		scale.MaxItemBytes = *scaleMaxItemBytes
+		allTests = append(allTests, scale.GetTests()...)
+	}
+
+	return allTests
 }
@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/ready"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/endpoints"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// The endpoint suites can be run with go test, as subtests named
+// Suite/Element/ID, e.g.
+//
+//	PERIDOT_ROOT=http://localhost:3005 go test -run 'TestEndpoints/endpoints/projects/' -v
+//
+// The environment variables are:
+//
+//	PERIDOT_ROOT     root URL of the API under test; if unset, the
+//	                 tests are skipped
+//	PERIDOT_DB       Postgres connection string, as for -db
+//	PERIDOT_RESTORE  restore mode, as for -restore; defaults to auto
+const (
+	envRoot    = "PERIDOT_ROOT"
+	envDB      = "PERIDOT_DB"
+	envRestore = "PERIDOT_RESTORE"
+)
+
+// namedTest is a TestFunc together with the names it reports,
+// and its annotations.
+type namedTest struct {
+	suite, element, id string
+	planned            *testresult.TestResult
+	t                  testresult.TestFunc
+}
+
+// TestEndpoints runs each test from endpoints.GetTests() as a
+// subtest, restoring the fixture before each one as the harness
+// does.
+func TestEndpoints(t *testing.T) {
+	root := os.Getenv(envRoot)
+	if root == "" {
+		t.Skipf("%s is not set", envRoot)
+	}
+
+	err := ready.Wait(&ready.Config{
+		Root:       root,
+		Admin:      utils.Identity("admin"),
+		Timeout:    30 * time.Second,
+		Backoff:    250 * time.Millisecond,
+		MaxBackoff: 4 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("API under test is not ready: %v", err)
+	}
+
+	mode := os.Getenv(envRestore)
+	if mode == "" {
+		mode = fixtures.ModeAuto
+	}
+	restorer, err := fixtures.NewRestorer(root, mode, os.Getenv(envDB))
+	if err != nil {
+		t.Fatalf("Error setting fixtures: %v", err)
+	}
+	defer restorer.Close()
+
+	annotations, err := readAnnotations("test/annotations.json")
+	if err != nil {
+		t.Fatalf("Error reading annotations: %v", err)
+	}
+
+	tests := endpoints.GetTests()
+	nts := []*namedTest{}
+	for i, p := range probeTests(tests) {
+		annotate(p, annotations)
+		nts = append(nts, &namedTest{suite: p.Suite, element: p.Element, id: p.ID, planned: p, t: tests[i]})
+	}
+
+	for _, suite := range groupTests(nts) {
+		suite := suite
+		t.Run(suite[0][0].suite, func(t *testing.T) {
+			for _, element := range suite {
+				element := element
+				t.Run(element[0].element, func(t *testing.T) {
+					for _, nt := range element {
+						nt := nt
+						t.Run(nt.id, func(t *testing.T) {
+							if nt.planned.Skipped {
+								t.Skipf("skipped: %s", reasonWithIssue(nt.planned))
+							}
+							err := restorer.Restore()
+							if err != nil {
+								t.Fatalf("Error restoring fixture before test: %v", err)
+							}
+							r := nt.t(root)
+							utils.CheckGolden(r)
+							annotate(r, annotations)
+							reportResult(t, r)
+						})
+					}
+				})
+			}
+		})
+	}
+}
+
+// reportResult maps a TestResult's outcome onto t. go test has no
+// expected failures, so they are reported as skips.
+func reportResult(t *testing.T, r *testresult.TestResult) {
+	switch r.Outcome() {
+	case testresult.Pass:
+		return
+	case testresult.Skip:
+		t.Skipf("skipped: %s", reasonWithIssue(r))
+	case testresult.XFail:
+		t.Skipf("expected failure: %s", reasonWithIssue(r))
+	case testresult.XPass:
+		t.Errorf("UNEXPECTED PASS: expected to fail because %s; remove its expected failure from the annotations if the bug is fixed", reasonWithIssue(r))
+		return
+	}
+	msg := "response did not match"
+	if r.FailError != nil {
+		msg = r.FailError.Error()
+	}
+	t.Errorf("step %s failed: %s\n    Wanted: %s\n    Got:    %s", r.FailStep, msg, r.Wanted, r.Got)
+}
+
+// groupTests groups tests by suite, and then by element, keeping
+// the order in which each first appears.
+func groupTests(nts []*namedTest) [][][]*namedTest {
+	suites := [][][]*namedTest{}
+	suiteIdx := map[string]int{}
+	elementIdx := map[[2]string]int{}
+	for _, nt := range nts {
+		si, ok := suiteIdx[nt.suite]
+		if !ok {
+			si = len(suites)
+			suiteIdx[nt.suite] = si
+			suites = append(suites, nil)
+		}
+		key := [2]string{nt.suite, nt.element}
+		ei, ok := elementIdx[key]
+		if !ok {
+			ei = len(suites[si])
+			elementIdx[key] = ei
+			suites[si] = append(suites[si], nil)
+		}
+		suites[si][ei] = append(suites[si][ei], nt)
+	}
+	return suites
+}
@@ -0,0 +1,179 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// testRun is one run of a test, in one round of a repeated run.
+type testRun struct {
+	res *testresult.TestResult
+
+	// prev is the index of the test that ran just before it in
+	// the round, or -1 if it ran first.
+	prev int
+}
+
+// testOrder returns the order in which to run n tests in a round:
+// as they were given, or shuffled. Each round's order depends only
+// on the seed and the round, so it can be reproduced.
+func testOrder(n int, round int, shuffle bool, seed int64) []int {
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	if shuffle {
+		rng := rand.New(rand.NewSource(seed + int64(round)))
+		rng.Shuffle(n, func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	return order
+}
+
+// summarizeRuns picks one result for each test to show in the
+// results table: its first failing run if it has one, since that
+// is the one worth looking at, or else its last run.
+func summarizeRuns(runs [][]*testRun) []*testresult.TestResult {
+	rs := []*testresult.TestResult{}
+	for _, trs := range runs {
+		if len(trs) == 0 {
+			continue
+		}
+		r := trs[len(trs)-1].res
+		for _, tr := range trs {
+			if o := tr.res.Outcome(); o == testresult.Fail || o == testresult.XPass {
+				r = tr.res
+				break
+			}
+		}
+		rs = append(rs, r)
+	}
+	return rs
+}
+
+// printRepeatReport prints how often each test passed over the
+// rounds, and for tests that sometimes passed and sometimes failed,
+// which tests had run just before their failures but never just
+// before a pass. Those are likely to leave behind state that the
+// failing test depends on. It returns whether any test's outcome
+// varied.
+func printRepeatReport(runs [][]*testRun, rounds int, shuffle *shuffleFlag, noReset bool) bool {
+	always, never, flaky := 0, 0, []int{}
+	for i, trs := range runs {
+		passes := countPasses(trs)
+		switch {
+		case passes == len(trs):
+			always++
+		case passes == 0:
+			never++
+		default:
+			flaky = append(flaky, i)
+		}
+	}
+
+	fmt.Printf("\nPass rates over %d rounds", rounds)
+	if shuffle.on {
+		fmt.Printf(", shuffled with seed %d", shuffle.seed)
+	}
+	if noReset {
+		fmt.Printf(", without resetting the fixture between tests")
+	}
+	fmt.Printf(":\n")
+	fmt.Printf("  %d tests passed every run, %d failed every run, %d passed only sometimes\n", always, never, len(flaky))
+	if shuffle.on {
+		fmt.Printf("  to reproduce this order, rerun with -shuffle=%d -repeat %d", shuffle.seed, rounds)
+		if noReset {
+			fmt.Printf(" -no-reset")
+		}
+		fmt.Printf("\n")
+	}
+
+	if len(flaky) == 0 {
+		return false
+	}
+
+	// sort by pass rate, lowest first
+	sort.SliceStable(flaky, func(a, b int) bool {
+		ra := float64(countPasses(runs[flaky[a]])) / float64(len(runs[flaky[a]]))
+		rb := float64(countPasses(runs[flaky[b]])) / float64(len(runs[flaky[b]]))
+		return ra < rb
+	})
+
+	fmt.Printf("\nTests that passed only sometimes:\n")
+	w := tabwriter.NewWriter(os.Stdout, 8, 4, 1, ' ', 0)
+	for _, i := range flaky {
+		trs := runs[i]
+		r := trs[0].res
+		passes := countPasses(trs)
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%d/%d\t%.0f%%\n", r.Suite, r.Element, r.ID, passes, len(trs), 100*float64(passes)/float64(len(trs)))
+	}
+	w.Flush()
+
+	if shuffle.on || noReset {
+		printSuspects(runs, flaky)
+	}
+
+	return true
+}
+
+// printSuspects prints, for each flaky test, the tests that ran
+// just before it when it failed, but never just before it when it
+// passed.
+func printSuspects(runs [][]*testRun, flaky []int) {
+	fmt.Printf("\nPossible order dependencies:\n")
+	found := false
+	for _, i := range flaky {
+		beforePass := map[int]bool{}
+		beforeFail := map[int]int{}
+		for _, tr := range runs[i] {
+			if tr.res.Success {
+				beforePass[tr.prev] = true
+			} else {
+				beforeFail[tr.prev]++
+			}
+		}
+
+		suspects := []int{}
+		for prev := range beforeFail {
+			if !beforePass[prev] {
+				suspects = append(suspects, prev)
+			}
+		}
+		if len(suspects) == 0 {
+			continue
+		}
+		sort.Ints(suspects)
+
+		found = true
+		r := runs[i][0].res
+		fmt.Printf("  %s:%s:%s failed after:\n", r.Suite, r.Element, r.ID)
+		for _, prev := range suspects {
+			if prev < 0 {
+				fmt.Printf("    (nothing; it ran first) %d times\n", beforeFail[prev])
+				continue
+			}
+			p := runs[prev][0].res
+			fmt.Printf("    %s:%s:%s %d times\n", p.Suite, p.Element, p.ID, beforeFail[prev])
+		}
+	}
+	if !found {
+		fmt.Printf("  none found; the failures may be timing-dependent\n")
+	}
+}
+
+// countPasses returns how many of a test's runs passed.
+func countPasses(trs []*testRun) int {
+	n := 0
+	for _, tr := range trs {
+		if tr.res.Success {
+			n++
+		}
+	}
+	return n
+}
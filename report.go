@@ -0,0 +1,121 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/swinslow/peridot-api-testing/internal/htmlreport"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// outcomeLabels are how each outcome is shown in the results table.
+var outcomeLabels = map[testresult.Outcome]string{
+	testresult.Pass:  "ok",
+	testresult.Fail:  "FAIL",
+	testresult.Skip:  "skip",
+	testresult.XFail: "xfail",
+	testresult.XPass: "XPASS",
+}
+
+// printResults prints the results table and the count of each
+// outcome. It returns whether the run failed: that is, whether any
+// test failed or unexpectedly passed.
+func printResults(allRs []*testresult.TestResult) bool {
+	// set up tabwriter for outputting test result table
+	w := tabwriter.NewWriter(os.Stdout, 8, 4, 1, ' ', 0)
+
+	counts := map[testresult.Outcome]int{}
+	for _, r := range allRs {
+		o := r.Outcome()
+		counts[o]++
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Suite, r.Element, r.ID, outcomeLabels[o])
+	}
+	w.Flush()
+
+	fmt.Printf("\n%d passed, %d failed, %d skipped, %d expected failures, %d unexpected passes\n",
+		counts[testresult.Pass], counts[testresult.Fail], counts[testresult.Skip], counts[testresult.XFail], counts[testresult.XPass])
+
+	return counts[testresult.Fail] > 0 || counts[testresult.XPass] > 0
+}
+
+// printDetails prints why tests were skipped or expected to fail,
+// warns about tests that unexpectedly passed, and prints the
+// details of failing tests.
+func printDetails(allRs []*testresult.TestResult) {
+	byOutcome := map[testresult.Outcome][]*testresult.TestResult{}
+	for _, r := range allRs {
+		byOutcome[r.Outcome()] = append(byOutcome[r.Outcome()], r)
+	}
+
+	if rs := byOutcome[testresult.Skip]; len(rs) > 0 {
+		fmt.Printf("\nSkipped:\n")
+		for _, r := range rs {
+			fmt.Printf("  %s:%s:%s: %s\n", r.Suite, r.Element, r.ID, reasonWithIssue(r))
+		}
+	}
+
+	if rs := byOutcome[testresult.XFail]; len(rs) > 0 {
+		fmt.Printf("\nExpected failures:\n")
+		for _, r := range rs {
+			fmt.Printf("  %s:%s:%s: %s\n", r.Suite, r.Element, r.ID, reasonWithIssue(r))
+		}
+	}
+
+	if rs := byOutcome[testresult.XPass]; len(rs) > 0 {
+		fmt.Printf("\n!!!!!!!!!!\n\n")
+		fmt.Printf("UNEXPECTED PASSES: %d tests are expected to fail, but passed.\n", len(rs))
+		fmt.Printf("The bugs they are marked with may have been fixed; if so,\n")
+		fmt.Printf("remove their expected failures from the annotations.\n\n")
+		for _, r := range rs {
+			fmt.Printf("  %s:%s:%s: %s\n", r.Suite, r.Element, r.ID, reasonWithIssue(r))
+		}
+		fmt.Printf("\n!!!!!!!!!!\n")
+	}
+
+	if rs := byOutcome[testresult.Fail]; len(rs) > 0 {
+		// print details of failing tests
+		fmt.Printf("\n\n==========\n\n")
+		for _, r := range rs {
+			fmt.Printf("%s:%s:%s\n", r.Suite, r.Element, r.ID)
+			fmt.Printf("    Status: FAIL\n")
+			fmt.Printf("    Step:   %s\n", r.FailStep)
+			fmt.Printf("    Errors: %v\n", r.FailError)
+			fmt.Printf("    Wanted: %s\n", r.Wanted)
+			fmt.Printf("    Got:    %s\n", r.Got)
+			fmt.Printf("\n==========\n\n")
+		}
+	}
+}
+
+// reasonWithIssue describes why a test was skipped or is expected
+// to fail.
+func reasonWithIssue(r *testresult.TestResult) string {
+	if r.Issue == "" {
+		return r.Reason
+	}
+	return fmt.Sprintf("%s (%s)", r.Reason, r.Issue)
+}
+
+// writeHTMLReport writes the HTML report of the results to a file.
+func writeHTMLReport(filename string, allRs []*testresult.TestResult, run *htmlreport.Run) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+
+	err = htmlreport.Write(f, allRs, run)
+	if err != nil {
+		f.Close()
+		return err
+	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nWrote HTML report to %s\n", filename)
+	return nil
+}
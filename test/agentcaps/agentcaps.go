@@ -0,0 +1,156 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package agentcaps
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// ExpectationsFile is the path to the JSON file holding the table
+// of documented expectations for this suite.
+var ExpectationsFile = "test/agentcaps/expectations.json"
+
+// expectation is one row of the expectations table: a job config
+// sent to a particular agent, and how the API should respond.
+type expectation struct {
+	// ID identifies the test within the element.
+	ID string `json:"id"`
+
+	// AgentID is the fixture agent that the job targets.
+	AgentID uint32 `json:"agent_id"`
+
+	// Config is the job's config.
+	Config json.RawMessage `json:"config"`
+
+	// Code is the expected HTTP status code: 201 if the job
+	// should be accepted, or an error code if not.
+	Code int `json:"code"`
+
+	// Note explains why this is the expected behavior.
+	Note string `json:"note"`
+}
+
+// GetTests returns all of the agent capability test suites, one
+// for each row in the expectations table.
+func GetTests() []testresult.TestFunc {
+	exps, err := readExpectations(ExpectationsFile)
+	if err != nil {
+		return []testresult.TestFunc{expectationsError(err)}
+	}
+
+	allTests := []testresult.TestFunc{}
+	for _, e := range exps {
+		allTests = append(allTests, capabilityTest(e))
+	}
+	return allTests
+}
+
+// readExpectations reads the expectations table from a file.
+func readExpectations(filename string) ([]*expectation, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var exps []*expectation
+	err = json.Unmarshal(b, &exps)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	return exps, nil
+}
+
+// expectationsError returns a test that always fails because the
+// expectations table could not be read, so that the problem is
+// reported alongside the other results.
+func expectationsError(err error) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "agentcaps",
+			Element: "repopulls/{id}/jobs",
+			ID:      "expectations",
+		}
+
+		utils.FailTest(res, "0", err)
+		return res
+	}
+}
+
+// capabilityTest returns a test that creates a job on repopull 3,
+// which has no jobs in the fixture, for the agent and config in e,
+// and checks that the API accepts or rejects it as expected.
+func capabilityTest(e *expectation) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "agentcaps",
+			Element: "repopulls/{id}/jobs",
+			ID:      e.ID,
+		}
+
+		url := root + "/repopulls/3/jobs"
+		body := fmt.Sprintf(`{"agent_id": %d, "priorjob_ids": [], "is_ready": false, "config": %s}`, e.AgentID, string(e.Config))
+
+		// first, send POST to create the job
+		err := utils.Post(res, "1", url, body, e.Code, "operator")
+		if err != nil {
+			res.FailError = fmt.Errorf("%v (%s)", err, e.Note)
+			return res
+		}
+
+		if e.Code != 201 {
+			// rejected, as expected; make sure it says why and
+			// that no job was created
+			if !utils.IsError(res) {
+				utils.FailTest(res, "2", fmt.Errorf("expected error message in response (%s)", e.Note))
+				return res
+			}
+
+			err = utils.GetContent(res, "3", url, 200, "viewer")
+			if err != nil {
+				return res
+			}
+			var list struct {
+				Jobs []json.RawMessage `json:"jobs"`
+			}
+			err = json.Unmarshal(res.Got, &list)
+			if err != nil {
+				utils.FailTest(res, "4", err)
+				return res
+			}
+			if len(list.Jobs) != 0 {
+				utils.FailTest(res, "4", fmt.Errorf("expected no jobs on repopull 3, got %d (%s)", len(list.Jobs), e.Note))
+				return res
+			}
+
+			utils.Pass(res)
+			return res
+		}
+
+		// accepted, as expected; confirm that the job was stored
+		// with the requested agent and config
+		res.Wanted = `{"id": 5}`
+		if !utils.IsMatch(res) {
+			utils.FailMatch(res, "2")
+			return res
+		}
+
+		res.Wanted = fmt.Sprintf(`{"job":{"id":5, "repopull_id":3, "agent_id":%d, "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":%s}}`, e.AgentID, string(e.Config))
+		err = utils.GetContent(res, "3", root+"/jobs/5", 200, "viewer")
+		if err != nil {
+			return res
+		}
+		if !utils.IsMatch(res) {
+			utils.FailMatch(res, "4")
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
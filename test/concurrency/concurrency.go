@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package concurrency
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// numClients is the number of simultaneous requests that each
+// test sends.
+const numClients = 20
+
+// GetTests returns all of the concurrency test suites.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	allTests = append(allTests, getProjectsTests()...)
+	allTests = append(allTests, getRepoBranchesTests()...)
+	allTests = append(allTests, getJobsTests()...)
+
+	return allTests
+}
+
+// response holds the outcome of one of several simultaneous calls.
+type response struct {
+	code int
+	body []byte
+	err  error
+}
+
+// fire sends n POST requests at the same time, with bodies built
+// by bodyFunc, and waits for all of them to complete. The
+// responses are returned in the same order as the bodies, and are
+// recorded in res's transcript as step, in the order they arrive.
+func fire(res *testresult.TestResult, step string, n int, url string, bodyFunc func(int) string, ghUsername string) []*response {
+	rs := make([]*response, n)
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			body := bodyFunc(i)
+			// wait until every goroutine is ready, so that the
+			// requests arrive as close together as possible
+			<-start
+			code, b, err := utils.Send(res, step, "POST", url, body, ghUsername)
+			rs[i] = &response{code: code, body: b, err: err}
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+	return rs
+}
+
+// checkCreated confirms that every response is a 201 with a JSON
+// body of the form {"id": N}, and that all of the IDs are distinct.
+// It returns the IDs in the same order as the responses. On
+// failure, it fills in the failure fields in the TestResult and
+// returns an error.
+func checkCreated(res *testresult.TestResult, step string, rs []*response) ([]uint32, error) {
+	ids := make([]uint32, len(rs))
+	seen := map[uint32]int{}
+
+	for i, r := range rs {
+		err := checkNoServerError(res, step, i, r)
+		if err != nil {
+			return nil, err
+		}
+
+		if r.code != 201 {
+			res.Got = r.body
+			err = fmt.Errorf("request %d: expected HTTP status code 201, got %d", i, r.code)
+			utils.FailTest(res, step, err)
+			return nil, err
+		}
+
+		var created struct {
+			ID uint32 `json:"id"`
+		}
+		err = json.Unmarshal(r.body, &created)
+		if err != nil {
+			res.Got = r.body
+			err = fmt.Errorf("request %d: could not parse response: %v", i, err)
+			utils.FailTest(res, step, err)
+			return nil, err
+		}
+
+		if prev, ok := seen[created.ID]; ok {
+			res.Got = r.body
+			err = fmt.Errorf("requests %d and %d both returned ID %d", prev, i, created.ID)
+			utils.FailTest(res, step, err)
+			return nil, err
+		}
+		seen[created.ID] = i
+		ids[i] = created.ID
+	}
+
+	return ids, nil
+}
+
+// checkNoServerError confirms that the request was completed and
+// did not return a 5xx status code.
+func checkNoServerError(res *testresult.TestResult, step string, i int, r *response) error {
+	if r.err != nil {
+		err := fmt.Errorf("request %d: %v", i, r.err)
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	if r.code >= 500 {
+		res.Got = r.body
+		err := fmt.Errorf("request %d: got server error HTTP status code %d", i, r.code)
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	return nil
+}
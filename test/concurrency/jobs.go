@@ -0,0 +1,88 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package concurrency
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+func getJobsTests() []testresult.TestFunc {
+	return []testresult.TestFunc{
+		jobsSubPostConcurrentOperator,
+	}
+}
+
+// ===== POST /repopulls/id/jobs
+
+func jobsSubPostConcurrentOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "concurrency",
+		Element: "repopulls/{id}/jobs",
+		ID:      "POST (operator, concurrent)",
+	}
+
+	// repopull 3 has no jobs in the fixture, so every job listed
+	// afterwards must come from one of these requests
+	url := root + "/repopulls/3/jobs"
+
+	// first, send many POSTs at once, each with a distinct config
+	rs := fire(res, "1", numClients, url, func(i int) string {
+		return fmt.Sprintf(`{"agent_id": 1, "priorjob_ids": [], "is_ready": false, "config": {"kv": {"client": "%d"}}}`, i)
+	}, "operator")
+	res.Wanted = fmt.Sprintf("%d distinct IDs", numClients)
+	ids, err := checkCreated(res, "1", rs)
+	if err != nil {
+		return res
+	}
+
+	// now, confirm that the jobs listed for the repopull are
+	// exactly the ones that were returned, and that each ID has
+	// the config from the request that returned it
+	err = utils.GetContent(res, "2", url, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	var list struct {
+		Jobs []struct {
+			ID     uint32 `json:"id"`
+			Config struct {
+				KV map[string]string `json:"kv"`
+			} `json:"config"`
+		} `json:"jobs"`
+	}
+	err = json.Unmarshal(res.Got, &list)
+	if err != nil {
+		utils.FailTest(res, "3", err)
+		return res
+	}
+
+	listedIDs := []uint32{}
+	listedClients := map[uint32]string{}
+	for _, j := range list.Jobs {
+		listedIDs = append(listedIDs, j.ID)
+		listedClients[j.ID] = j.Config.KV["client"]
+	}
+	returnedIDs := append([]uint32{}, ids...)
+	sort.Slice(listedIDs, func(i, j int) bool { return listedIDs[i] < listedIDs[j] })
+	sort.Slice(returnedIDs, func(i, j int) bool { return returnedIDs[i] < returnedIDs[j] })
+	if fmt.Sprint(listedIDs) != fmt.Sprint(returnedIDs) {
+		utils.FailTest(res, "4", fmt.Errorf("returned job IDs %v but listed job IDs %v", returnedIDs, listedIDs))
+		return res
+	}
+
+	for i, id := range ids {
+		if listedClients[id] != fmt.Sprintf("%d", i) {
+			utils.FailTest(res, "5", fmt.Errorf("request %d was returned job ID %d, but that job has config for request %q", i, id, listedClients[id]))
+			return res
+		}
+	}
+
+	utils.Pass(res)
+	return res
+}
@@ -0,0 +1,84 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package concurrency
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+func getProjectsTests() []testresult.TestFunc {
+	return []testresult.TestFunc{
+		projectsPostConcurrentOperator,
+	}
+}
+
+// ===== POST /projects
+
+func projectsPostConcurrentOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "concurrency",
+		Element: "projects",
+		ID:      "POST (operator, concurrent)",
+	}
+
+	url := root + "/projects"
+
+	// first, send many POSTs at once, each with a distinct name
+	rs := fire(res, "1", numClients, url, func(i int) string {
+		return fmt.Sprintf(`{"name": "conc-%d", "fullname": "The conc-%d Project"}`, i, i)
+	}, "operator")
+	res.Wanted = fmt.Sprintf("%d distinct IDs", numClients)
+	ids, err := checkCreated(res, "1", rs)
+	if err != nil {
+		return res
+	}
+
+	// now, confirm that the list has exactly one project for
+	// each request, with the ID that the request returned
+	err = utils.GetContent(res, "2", url, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	var list struct {
+		Projects []struct {
+			ID   uint32 `json:"id"`
+			Name string `json:"name"`
+		} `json:"projects"`
+	}
+	err = json.Unmarshal(res.Got, &list)
+	if err != nil {
+		utils.FailTest(res, "3", err)
+		return res
+	}
+
+	// the 3 fixture projects plus one per request
+	if len(list.Projects) != 3+numClients {
+		utils.FailTest(res, "3", fmt.Errorf("expected %d projects, got %d", 3+numClients, len(list.Projects)))
+		return res
+	}
+
+	listed := map[string]uint32{}
+	for _, p := range list.Projects {
+		listed[p.Name] = p.ID
+	}
+	for i, id := range ids {
+		name := fmt.Sprintf("conc-%d", i)
+		listedID, ok := listed[name]
+		if !ok {
+			utils.FailTest(res, "4", fmt.Errorf("project %s (ID %d) not in list", name, id))
+			return res
+		}
+		if listedID != id {
+			utils.FailTest(res, "4", fmt.Errorf("project %s was returned with ID %d but listed with ID %d", name, id, listedID))
+			return res
+		}
+	}
+
+	utils.Pass(res)
+	return res
+}
@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package concurrency
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+func getRepoBranchesTests() []testresult.TestFunc {
+	return []testresult.TestFunc{
+		repoBranchesSubPostSameNameConcurrentOperator,
+	}
+}
+
+// ===== POST /repos/id/branches
+
+func repoBranchesSubPostSameNameConcurrentOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "concurrency",
+		Element: "repos/{id}/branches",
+		ID:      "POST (operator, concurrent same name)",
+	}
+
+	url := root + "/repos/2/branches"
+	body := `{"branch": "issue-47"}`
+
+	// first, send many POSTs at once, all for the same branch name
+	rs := fire(res, "1", numClients, url, func(i int) string { return body }, "operator")
+
+	// the duplicates may either all be accepted (deduplicated) or
+	// all but one rejected, but the rejections must be consistent
+	// and at least one request must succeed
+	created := 0
+	rejectedCode := 0
+	for i, r := range rs {
+		err := checkNoServerError(res, "1", i, r)
+		if err != nil {
+			return res
+		}
+
+		if r.code == 201 {
+			created++
+			continue
+		}
+		if r.code < 400 {
+			res.Got = r.body
+			utils.FailTest(res, "1", fmt.Errorf("request %d: expected HTTP status code 201 or 4xx, got %d", i, r.code))
+			return res
+		}
+		if rejectedCode != 0 && r.code != rejectedCode {
+			res.Got = r.body
+			utils.FailTest(res, "1", fmt.Errorf("request %d: duplicate rejected with HTTP status code %d, others rejected with %d", i, r.code, rejectedCode))
+			return res
+		}
+		rejectedCode = r.code
+	}
+	if created == 0 {
+		utils.FailTest(res, "1", fmt.Errorf("none of the %d requests created the branch", numClients))
+		return res
+	}
+	if rejectedCode != 0 && created != 1 {
+		utils.FailTest(res, "1", fmt.Errorf("%d requests created the branch and %d were rejected; expected exactly one to succeed", created, numClients-created))
+		return res
+	}
+
+	// now, send one more duplicate on its own; it must be handled
+	// the same way as the concurrent duplicates were
+	wantCode := 201
+	if rejectedCode != 0 {
+		wantCode = rejectedCode
+	}
+	err := utils.Post(res, "2", url, body, wantCode, "operator")
+	if err != nil {
+		return res
+	}
+
+	// finally, confirm that the branch is listed exactly once
+	// should be returned in alphabetical order
+	res.Wanted = utils.Golden(res, "3")
+	err = utils.GetContent(res, "3", url, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
@@ -0,0 +1,36 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package dbstate checks the API's database directly after API
+// calls, to catch the API reporting success for changes it did not
+// fully make. Table and column names are those of the API's schema.
+package dbstate
+
+import (
+	"github.com/swinslow/peridot-api-testing/internal/dbinspect"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// DB is the inspector for the API's database. It must be set
+// before the tests are run.
+var DB *dbinspect.Inspector
+
+// GetTests returns all of the database state test suites.
+func GetTests() []testresult.TestFunc {
+	return []testresult.TestFunc{
+		fixtureRowCounts,
+		projectsPostRow,
+		projectsDeleteDependents,
+		subprojectsDeleteDependents,
+		agentsPutRow,
+		jobsDeleteRow,
+	}
+}
+
+// expect fails the step if err is not nil, and returns err.
+func expect(res *testresult.TestResult, step string, err error) error {
+	if err != nil {
+		utils.FailTest(res, step, err)
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package dbstate
+
+import (
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// ===== fixture
+
+func fixtureRowCounts(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "fixture",
+		ID:      "row counts",
+	}
+
+	counts := []struct {
+		table string
+		n     int
+	}{
+		{"users", 5},
+		{"projects", 3},
+		{"subprojects", 4},
+		{"repos", 4},
+		{"agents", 4},
+		{"jobs", 4},
+	}
+
+	for _, c := range counts {
+		if expect(res, "1", DB.ExpectCount(c.n, c.table, "")) != nil {
+			return res
+		}
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== POST /projects
+
+func projectsPostRow(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "projects",
+		ID:      "POST",
+	}
+
+	res.Wanted = utils.Golden(res, "1")
+	err := utils.Post(res, "1", root+"/projects", `{"name": "inform", "fullname": "The inform Project"}`, 201, "operator")
+	if err != nil {
+		return res
+	}
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "1")
+		return res
+	}
+
+	if expect(res, "2", DB.ExpectOne("projects", "id = $1 AND name = $2 AND fullname = $3", 4, "inform", "The inform Project")) != nil {
+		return res
+	}
+	if expect(res, "3", DB.ExpectCount(4, "projects", "")) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== DELETE /projects/2
+
+func projectsDeleteDependents(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "projects/{id}",
+		ID:      "DELETE (dependents)",
+	}
+
+	// project 2 has subprojects 1-3, which have repos 1-3
+	res.Wanted = ``
+	err := utils.Delete(res, "1", root+"/projects/2", ``, 204, "admin")
+	if err != nil {
+		return res
+	}
+
+	if expect(res, "2", DB.ExpectNone("projects", "id = $1", 2)) != nil {
+		return res
+	}
+	if expect(res, "3", DB.ExpectNone("subprojects", "project_id = $1", 2)) != nil {
+		return res
+	}
+	if expect(res, "4", DB.ExpectNone("repos", "subproject_id IN (1, 2, 3)")) != nil {
+		return res
+	}
+
+	// and the other project's rows are untouched
+	if expect(res, "5", DB.ExpectOne("subprojects", "project_id = $1", 3)) != nil {
+		return res
+	}
+	if expect(res, "6", DB.ExpectOne("repos", "subproject_id = $1", 4)) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== DELETE /subprojects/2
+
+func subprojectsDeleteDependents(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "subprojects/{id}",
+		ID:      "DELETE (dependents)",
+	}
+
+	// subproject 2 has repos 1 and 2
+	res.Wanted = ``
+	err := utils.Delete(res, "1", root+"/subprojects/2", ``, 204, "admin")
+	if err != nil {
+		return res
+	}
+
+	if expect(res, "2", DB.ExpectNone("subprojects", "id = $1", 2)) != nil {
+		return res
+	}
+	if expect(res, "3", DB.ExpectNone("repos", "subproject_id = $1", 2)) != nil {
+		return res
+	}
+	if expect(res, "4", DB.ExpectCount(2, "repos", "")) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== PUT /agents/2
+
+func agentsPutRow(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "agents/{id}",
+		ID:      "PUT",
+	}
+
+	res.Wanted = ``
+	err := utils.Put(res, "1", root+"/agents/2", `{"is_active": false, "port": 2099}`, 204, "operator")
+	if err != nil {
+		return res
+	}
+
+	if expect(res, "2", DB.ExpectOne("agents", "id = $1 AND is_active = false AND port = $2", 2, 2099)) != nil {
+		return res
+	}
+	// the fields that weren't given are unchanged
+	if expect(res, "3", DB.ExpectOne("agents", "id = $1 AND name = $2 AND is_codereader = true", 2, "read-magic")) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== DELETE /jobs/4
+
+func jobsDeleteRow(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "dbstate",
+		Element: "jobs/{id}",
+		ID:      "DELETE",
+	}
+
+	res.Wanted = ``
+	err := utils.Delete(res, "1", root+"/jobs/4", ``, 204, "admin")
+	if err != nil {
+		return res
+	}
+
+	if expect(res, "2", DB.ExpectNone("jobs", "id = $1", 4)) != nil {
+		return res
+	}
+	if expect(res, "3", DB.ExpectCount(3, "jobs", "")) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
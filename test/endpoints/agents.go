@@ -33,12 +33,7 @@ func agentsGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/agents"
 
-	res.Wanted = `{"agents":[
-		{"id":1, "name":"do-magic", "is_active":true, "address":"https://example.com/do-magic", "port":2087, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true},
-		{"id":3, "name":"disabled", "is_active":false, "address":"localhost", "port":2057, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":4, "name":"wevs", "is_active":true, "address":"localhost", "port":5010, "is_codereader":true, "is_spdxreader":true, "is_codewriter":true, "is_spdxwriter":false}
-	]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -66,7 +61,7 @@ func agentsPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new agent
 	body := `{"name":"idsearcher", "is_active":true, "address":"localhost", "port":9014, "is_codereader":true, "is_spdxreader":false, "is_codewriter":false, "is_spdxwriter":true}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -78,13 +73,7 @@ func agentsPostOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that a new agent was actually added
-	res.Wanted = `{"agents":[
-		{"id":1, "name":"do-magic", "is_active":true, "address":"https://example.com/do-magic", "port":2087, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true},
-		{"id":3, "name":"disabled", "is_active":false, "address":"localhost", "port":2057, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":4, "name":"wevs", "is_active":true, "address":"localhost", "port":5010, "is_codereader":true, "is_spdxreader":true, "is_codewriter":true, "is_spdxwriter":false},
-		{"id":5, "name":"idsearcher", "is_active":true, "address":"localhost", "port":9014, "is_codereader":true, "is_spdxreader":false, "is_codewriter":false, "is_spdxwriter":true}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -110,7 +99,7 @@ func agentsGetOneViewer(root string) *testresult.TestResult {
 
 	url := root + "/agents/2"
 
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true}}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -150,7 +139,7 @@ func agentsPutOneOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the agent was actually updated
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":false, "address":"https://example.com/new-address", "port":3077, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -188,7 +177,7 @@ func agentsPutOneIsActiveOnlyOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the agent was actually updated
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":false, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -226,7 +215,7 @@ func agentsPutOneStatusOnlyOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the agent was actually updated
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":false, "address":"https://example.com/new-address", "port":3077, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -264,7 +253,7 @@ func agentsPutOneAbilitiesOnlyOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the agent was actually updated
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -289,7 +278,7 @@ func agentsPutOneViewer(root string) *testresult.TestResult {
 	url := root + "/agents/2"
 
 	body := `{"is_active":false, "address":"https://example.com/new-address", "port":3077, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Put(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -301,7 +290,7 @@ func agentsPutOneViewer(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the agent was NOT actually updated
-	res.Wanted = `{"agent":{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -341,11 +330,7 @@ func agentsDeleteOneAdmin(root string) *testresult.TestResult {
 
 	// now, confirm that the agent is gone
 	allURL := root + "/agents"
-	res.Wanted = `{"agents":[
-		{"id":1, "name":"do-magic", "is_active":true, "address":"https://example.com/do-magic", "port":2087, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":3, "name":"disabled", "is_active":false, "address":"localhost", "port":2057, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":4, "name":"wevs", "is_active":true, "address":"localhost", "port":5010, "is_codereader":true, "is_spdxreader":true, "is_codewriter":true, "is_spdxwriter":false}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -370,7 +355,7 @@ func agentsDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/agents/2"
 
 	// try and fail to delete the agent
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -383,12 +368,7 @@ func agentsDeleteOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the agent has NOT been deleted
 	allURL := root + "/agents"
-	res.Wanted = `{"agents":[
-		{"id":1, "name":"do-magic", "is_active":true, "address":"https://example.com/do-magic", "port":2087, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":2, "name":"read-magic", "is_active":true, "address":"https://example.com/read-magic", "port":2088, "is_codereader":true, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":true},
-		{"id":3, "name":"disabled", "is_active":false, "address":"localhost", "port":2057, "is_codereader":false, "is_spdxreader":true, "is_codewriter":false, "is_spdxwriter":false},
-		{"id":4, "name":"wevs", "is_active":true, "address":"localhost", "port":5010, "is_codereader":true, "is_spdxreader":true, "is_codewriter":true, "is_spdxwriter":false}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -20,7 +20,7 @@ func helloGet(root string) *testresult.TestResult {
 		ID:      "GET",
 	}
 
-	res.Wanted = `{"message": "hello"}`
+	res.Wanted = utils.Golden(res, "1")
 	url := root + "/hello"
 	err := utils.GetContent(res, "1", url, 200, "none")
 	if err != nil {
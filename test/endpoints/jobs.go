@@ -30,11 +30,7 @@ func jobsSubGetOperator(root string) *testresult.TestResult {
 
 	url := root + "/repopulls/4/jobs"
 
-	res.Wanted = `{"jobs":[
-		{"id":2, "repopull_id":4, "agent_id":1, "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{}},
-		{"id":3, "repopull_id":4, "agent_id":2, "priorjob_ids": [2], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{"codereader": {"primary": {"path": "/somewhere"}}}},
-		{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2,3], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}}
-	]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -64,7 +60,7 @@ func jobsSubPostOperator(root string) *testresult.TestResult {
 	body := `{"agent_id":1, "is_ready":false, "priorjob_ids":[],
 		"config":{"kv": {"hi": "there", "hello": "world"}}
 	}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -78,9 +74,7 @@ func jobsSubPostOperator(root string) *testresult.TestResult {
 	// now, confirm that a new job was actually added
 	// this should be the only one for repopull 3 so we can reuse the same url
 	// priorjob_ids and some config vals should be absent
-	res.Wanted = `{"jobs":[
-		{"id":5, "repopull_id":3, "agent_id":1, "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hi": "there", "hello": "world"}}}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -106,7 +100,7 @@ func jobsGetOneViewer(root string) *testresult.TestResult {
 
 	url := root + "/jobs/4"
 
-	res.Wanted = `{"job":{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2,3], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}}}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -148,7 +142,7 @@ func jobsPutOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the job was actually updated
 	// is_ready should now be true
-	res.Wanted = `{"job":{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2,3], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -173,7 +167,7 @@ func jobsPutOneViewer(root string) *testresult.TestResult {
 	url := root + "/jobs/4"
 
 	body := `{"is_ready": true}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Put(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -186,7 +180,7 @@ func jobsPutOneViewer(root string) *testresult.TestResult {
 
 	// now, confirm that the job was NOT actually updated
 	// is_ready should still be false
-	res.Wanted = `{"job":{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2,3], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -228,10 +222,7 @@ func jobsDeleteOneAdmin(root string) *testresult.TestResult {
 	// NOTE that job ID 3 is also removed from priorjob_ids and config for job 4.
 	// FIXME the deleted job should not cascade in this way.
 	allURL := root + "/repopulls/4/jobs"
-	res.Wanted = `{"jobs":[
-		{"id":2, "repopull_id":4, "agent_id":1, "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{}},
-		{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hello":"world"}, "spdxreader": {"primary": {"path": "/path/wherever"}}}}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -256,7 +247,7 @@ func jobsDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/jobs/3"
 
 	// try and fail to delete the job
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -269,11 +260,7 @@ func jobsDeleteOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the job has NOT been deleted
 	allURL := root + "/repopulls/4/jobs"
-	res.Wanted = `{"jobs":[
-		{"id":2, "repopull_id":4, "agent_id":1, "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{}},
-		{"id":3, "repopull_id":4, "agent_id":2, "priorjob_ids": [2], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":true, "config":{"codereader": {"primary": {"path": "/somewhere"}}}},
-		{"id":4, "repopull_id":4, "agent_id":4, "priorjob_ids": [2,3], "started_at":"0001-01-01T00:00:00Z", "finished_at":"0001-01-01T00:00:00Z", "status":"startup", "health":"ok", "is_ready":false, "config":{"kv": {"hello":"world"}, "codereader": {"godeps": {"priorjob_id": 3}}, "spdxreader": {"primary": {"path": "/path/wherever"}, "godeps": {"priorjob_id": 3}}}}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -31,7 +31,7 @@ func projectsGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/projects"
 
-	res.Wanted = `{"projects":[{"id":1,"name":"xyzzy","fullname":"The xyzzy Project"},{"id":2,"name":"frotz","fullname":"The frotz Project"},{"id":3,"name":"gnusto","fullname":"The gnusto Project"}]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -59,7 +59,7 @@ func projectsPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new project
 	body := `{"name": "plugh", "fullname": "The plugh Project"}`
-	res.Wanted = `{"id": 4}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -71,7 +71,7 @@ func projectsPostOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that a new project was actually added
-	res.Wanted = `{"projects":[{"id":1,"name":"xyzzy","fullname":"The xyzzy Project"},{"id":2,"name":"frotz","fullname":"The frotz Project"},{"id":3,"name":"gnusto","fullname":"The gnusto Project"},{"id":4,"name":"plugh","fullname":"The plugh Project"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -97,7 +97,7 @@ func projectsPostViewer(root string) *testresult.TestResult {
 
 	// first, try and fail to add a new project
 	body := `{"name": "plugh", "fullname": "The plugh Project"}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -109,7 +109,7 @@ func projectsPostViewer(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that a new project was NOT actually added
-	res.Wanted = `{"projects":[{"id":1,"name":"xyzzy","fullname":"The xyzzy Project"},{"id":2,"name":"frotz","fullname":"The frotz Project"},{"id":3,"name":"gnusto","fullname":"The gnusto Project"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -133,7 +133,7 @@ func projectsGetOneViewer(root string) *testresult.TestResult {
 		ID:      "GET (viewer)",
 	}
 
-	res.Wanted = `{"project":{"id":2,"name":"frotz","fullname":"The frotz Project"}}`
+	res.Wanted = utils.Golden(res, "1")
 	url := root + "/projects/2"
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
@@ -174,7 +174,7 @@ func projectsPutOneOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the project was actually updated
-	res.Wanted = `{"project":{"id":2,"name":"plugh","fullname":"The plugh Project"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -199,7 +199,7 @@ func projectsPutOneViewer(root string) *testresult.TestResult {
 	url := root + "/projects/2"
 
 	body := `{"name": "plugh", "fullname": "The plugh Project"}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Put(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -211,7 +211,7 @@ func projectsPutOneViewer(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the project was NOT actually updated
-	res.Wanted = `{"project":{"id":2,"name":"frotz","fullname":"The frotz Project"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -251,7 +251,7 @@ func projectsDeleteOneAdmin(root string) *testresult.TestResult {
 
 	// now, confirm that the project is gone
 	allURL := root + "/projects"
-	res.Wanted = `{"projects":[{"id":1,"name":"xyzzy","fullname":"The xyzzy Project"},{"id":3,"name":"gnusto","fullname":"The gnusto Project"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -276,7 +276,7 @@ func projectsDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/projects/2"
 
 	// try and fail to delete the project
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -289,7 +289,7 @@ func projectsDeleteOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the project has NOT been deleted
 	allURL := root + "/projects"
-	res.Wanted = `{"projects":[{"id":1,"name":"xyzzy","fullname":"The xyzzy Project"},{"id":2,"name":"frotz","fullname":"The frotz Project"},{"id":3,"name":"gnusto","fullname":"The gnusto Project"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
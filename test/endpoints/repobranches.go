@@ -26,7 +26,7 @@ func repoBranchesSubGetViewer(root string) *testresult.TestResult {
 	url := root + "/repos/2/branches"
 
 	// should be returned in alphabetical order
-	res.Wanted = `{"branches":["dev","dev-2.1","master"]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -54,7 +54,7 @@ func repoBranchesSubPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new branch to the existing repo
 	body := `{"branch": "issue-47"}`
-	res.Wanted = `{"branch": "issue-47"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -67,7 +67,7 @@ func repoBranchesSubPostOperator(root string) *testresult.TestResult {
 
 	// now, confirm that a new repo branch was actually added
 	// should be returned in alphabetical order
-	res.Wanted = `{"branches":["dev","dev-2.1","issue-47","master"]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
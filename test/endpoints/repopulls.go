@@ -11,6 +11,12 @@ func getRepoPullsTests() []testresult.TestFunc {
 	return []testresult.TestFunc{
 		repoPullsSubGetViewer,
 		repoPullsSubWithCommitPostOperator,
+		repoPullsSubWithTagPostOperator,
+		repoPullsSubHeadPostOperator,
+		repoPullsSubWithEmptyCommitAndTagPostOperator,
+		repoPullsSubWithCommitAndTagPostOperator,
+		repoPullsSubOtherRepoBranchPostOperator,
+		repoPullsSubUnknownBranchPostOperator,
 		repoPullsGetOneViewer,
 		repoPullsDeleteOneAdmin,
 		repoPullsDeleteOneOperator,
@@ -28,10 +34,7 @@ func repoPullsSubGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/repos/2/branches/dev-2.1"
 
-	res.Wanted = `{"pulls":[
-		{"id":2,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"7864e74c9f54b1da4a64aaf7587ffa7880392233","spdx_id":""},
-		{"id":4,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"9f54b1da4a64aaf7587ffa78803922337864e74c","spdx_id":""}
-	]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -60,7 +63,7 @@ func repoPullsSubWithCommitPostOperator(root string) *testresult.TestResult {
 	// first, send POST to set up a repo pull with the requested commit
 	// NOTE this is a made-up commit + branch + repo so cannot actually get pulled
 	body := `{"commit": "803922337864e74c9f54b1da4a64aaf7587ffa78"}`
-	res.Wanted = `{"id":6}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -73,7 +76,7 @@ func repoPullsSubWithCommitPostOperator(root string) *testresult.TestResult {
 
 	// now, confirm that a new repo pull was actually added
 	// NOTE output and tag are omitempty so will not be included here
-	res.Wanted = `{"repopull":{"id":6,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"803922337864e74c9f54b1da4a64aaf7587ffa78","spdx_id":""}}`
+	res.Wanted = utils.Golden(res, "3")
 	repoPullURL := root + "/repopulls/6"
 	err = utils.GetContent(res, "3", repoPullURL, 200, "operator")
 	if err != nil {
@@ -89,6 +92,253 @@ func repoPullsSubWithCommitPostOperator(root string) *testresult.TestResult {
 	return res
 }
 
+func repoPullsSubWithTagPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, tag)",
+	}
+
+	url := root + "/repos/2/branches/dev-2.1"
+
+	// first, send POST to set up a repo pull with the requested tag
+	body := `{"tag": "v2.1.0"}`
+	res.Wanted = utils.Golden(res, "1")
+	err := utils.Post(res, "1", url, body, 201, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, confirm that the repo pull was added with the tag and
+	// without a commit, since it has not been pulled yet
+	res.Wanted = utils.Golden(res, "3")
+	repoPullURL := root + "/repopulls/6"
+	err = utils.GetContent(res, "3", repoPullURL, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func repoPullsSubHeadPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, head)",
+	}
+
+	url := root + "/repos/2/branches/dev-2.1"
+
+	// first, send POST with no commit or tag, to set up a repo pull
+	// for the current head of the branch
+	body := `{}`
+	res.Wanted = utils.Golden(res, "1")
+	err := utils.Post(res, "1", url, body, 201, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, confirm that the repo pull was added with no commit,
+	// since the head commit is not known until it is pulled
+	// NOTE tag is omitempty so will not be included here
+	res.Wanted = utils.Golden(res, "3")
+	repoPullURL := root + "/repopulls/6"
+	err = utils.GetContent(res, "3", repoPullURL, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func repoPullsSubWithEmptyCommitAndTagPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, empty commit and tag)",
+	}
+
+	url := root + "/repos/2/branches/dev-2.1"
+
+	// first, send POST with empty commit and tag; this should be
+	// treated the same as sending neither, i.e. the current head
+	body := `{"commit": "", "tag": ""}`
+	res.Wanted = utils.Golden(res, "1")
+	err := utils.Post(res, "1", url, body, 201, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, confirm that the repo pull was added for the head
+	res.Wanted = utils.Golden(res, "3")
+	repoPullURL := root + "/repopulls/6"
+	err = utils.GetContent(res, "3", repoPullURL, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func repoPullsSubWithCommitAndTagPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, commit and tag)",
+	}
+
+	url := root + "/repos/2/branches/dev-2.1"
+
+	// try and fail to set up a repo pull with both a commit and a
+	// tag, since they could refer to different commits
+	body := `{"commit": "803922337864e74c9f54b1da4a64aaf7587ffa78", "tag": "v2.1.0"}`
+	err := utils.Post(res, "1", url, body, 400, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsError(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, confirm that no repo pull was added
+	res.Wanted = utils.Golden(res, "3")
+	err = utils.GetContent(res, "3", url, 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func repoPullsSubOtherRepoBranchPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, branch of other repo)",
+	}
+
+	// dev-2.1 is a branch of repo 2, not repo 1
+	url := root + "/repos/1/branches/dev-2.1"
+
+	// try and fail to set up a repo pull on the wrong repo
+	body := `{"commit": "803922337864e74c9f54b1da4a64aaf7587ffa78"}`
+	err := utils.Post(res, "1", url, body, 404, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsError(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, confirm that repo 1 did not gain the branch
+	res.Wanted = utils.Golden(res, "3")
+	err = utils.GetContent(res, "3", root+"/repos/1/branches", 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func repoPullsSubUnknownBranchPostOperator(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "endpoints",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "POST (operator, unknown branch)",
+	}
+
+	url := root + "/repos/2/branches/no-such-branch"
+
+	// try and fail to set up a repo pull on a branch that does not
+	// exist, with either a commit or a tag
+	body := `{"commit": "803922337864e74c9f54b1da4a64aaf7587ffa78"}`
+	err := utils.Post(res, "1", url, body, 404, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsError(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	body = `{"tag": "v2.1.0"}`
+	err = utils.Post(res, "3", url, body, 404, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsError(res) {
+		utils.FailMatch(res, "4")
+		return res
+	}
+
+	// now, confirm that repo 2 did not gain the branch
+	res.Wanted = utils.Golden(res, "5")
+	err = utils.GetContent(res, "5", root+"/repos/2/branches", 200, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "6")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
 // ===== GET /repopulls/id
 
 func repoPullsGetOneViewer(root string) *testresult.TestResult {
@@ -100,7 +350,7 @@ func repoPullsGetOneViewer(root string) *testresult.TestResult {
 
 	url := root + "/repopulls/5"
 
-	res.Wanted = `{"repopull":{"id":5,"repo_id":1,"branch":"testing","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"b1da4a64aaf7587ffa78803922337864e74c9f54","spdx_id":""}}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -141,9 +391,7 @@ func repoPullsDeleteOneAdmin(root string) *testresult.TestResult {
 	// now, confirm that the repopull is gone
 	allURL := root + "/repos/2/branches/dev-2.1"
 
-	res.Wanted = `{"pulls":[
-		{"id":2,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"7864e74c9f54b1da4a64aaf7587ffa7880392233","spdx_id":""}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -168,7 +416,7 @@ func repoPullsDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/repopulls/4"
 
 	// try and fail to delete the repopull
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -182,10 +430,7 @@ func repoPullsDeleteOneOperator(root string) *testresult.TestResult {
 	// now, confirm that the repopull has NOT been deleted
 	allURL := root + "/repos/2/branches/dev-2.1"
 
-	res.Wanted = `{"pulls":[
-		{"id":2,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"7864e74c9f54b1da4a64aaf7587ffa7880392233","spdx_id":""},
-		{"id":4,"repo_id":2,"branch":"dev-2.1","started_at":"0001-01-01T00:00:00Z","finished_at":"0001-01-01T00:00:00Z","status":"startup","health":"ok","commit":"9f54b1da4a64aaf7587ffa78803922337864e74c","spdx_id":""}
-	]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -32,7 +32,7 @@ func reposGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/repos"
 
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"},{"id":3,"subproject_id":1,"name":"blorple-c","address":"https://example.com/blorple-c.git"},{"id":4,"subproject_id":4,"name":"girgol","address":"https://example.com/girgol.git"}]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -60,7 +60,7 @@ func reposPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new repo
 	body := `{"subproject_id": 2, "name": "filfre-webapp", "address": "https://example.com/filfre-webapp.git"}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -72,7 +72,7 @@ func reposPostOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that a new repo was actually added
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"},{"id":3,"subproject_id":1,"name":"blorple-c","address":"https://example.com/blorple-c.git"},{"id":4,"subproject_id":4,"name":"girgol","address":"https://example.com/girgol.git"},{"id":5,"subproject_id":2,"name":"filfre-webapp","address":"https://example.com/filfre-webapp.git"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -98,7 +98,7 @@ func reposSubGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/subprojects/2/repos"
 
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"}]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -126,7 +126,7 @@ func reposSubPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new repo
 	body := `{"name": "filfre-webapp", "address": "https://example.com/filfre-webapp.git"}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -139,7 +139,7 @@ func reposSubPostOperator(root string) *testresult.TestResult {
 
 	// now, confirm that a new repo was actually added
 	url = root + "/repos"
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"},{"id":3,"subproject_id":1,"name":"blorple-c","address":"https://example.com/blorple-c.git"},{"id":4,"subproject_id":4,"name":"girgol","address":"https://example.com/girgol.git"},{"id":5,"subproject_id":2,"name":"filfre-webapp","address":"https://example.com/filfre-webapp.git"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -165,7 +165,7 @@ func reposGetOneViewer(root string) *testresult.TestResult {
 
 	url := root + "/repos/2"
 
-	res.Wanted = `{"repo":{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"}}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -205,7 +205,7 @@ func reposPutOneOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the repo was actually updated
-	res.Wanted = `{"repo":{"id":2,"subproject_id":2,"name": "filfre-superapi", "address": "https://example.com/filfre-superapi.git"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -230,7 +230,7 @@ func reposPutOneViewer(root string) *testresult.TestResult {
 	url := root + "/repos/2"
 
 	body := `{"name": "filfre-superapi", "address": "https://example.com/filfre-superapi.git"}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Put(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -242,7 +242,7 @@ func reposPutOneViewer(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the repo was NOT actually updated
-	res.Wanted = `{"repo":{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -282,7 +282,7 @@ func reposDeleteOneAdmin(root string) *testresult.TestResult {
 
 	// now, confirm that the repo is gone
 	allURL := root + "/repos"
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":3,"subproject_id":1,"name":"blorple-c","address":"https://example.com/blorple-c.git"},{"id":4,"subproject_id":4,"name":"girgol","address":"https://example.com/girgol.git"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -307,7 +307,7 @@ func reposDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/repos/2"
 
 	// try and fail to delete the repo
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -320,7 +320,7 @@ func reposDeleteOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the repo has NOT been deleted
 	allURL := root + "/repos"
-	res.Wanted = `{"repos":[{"id":1,"subproject_id":2,"name":"filfre-core","address":"https://example.com/filfre-core.git"},{"id":2,"subproject_id":2,"name":"filfre-api","address":"https://example.com/filfre-api.git"},{"id":3,"subproject_id":1,"name":"blorple-c","address":"https://example.com/blorple-c.git"},{"id":4,"subproject_id":4,"name":"girgol","address":"https://example.com/girgol.git"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
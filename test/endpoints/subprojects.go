@@ -32,7 +32,7 @@ func subprojectsGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/subprojects"
 
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"},{"id":4,"project_id":3,"name":"girgol","fullname":"The girgol Subproject"}]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -60,7 +60,7 @@ func subprojectsPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new subproject
 	body := `{"project_id": 3, "name": "plugh", "fullname": "The plugh Subproject"}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -72,7 +72,7 @@ func subprojectsPostOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that a new subproject was actually added
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"},{"id":4,"project_id":3,"name":"girgol","fullname":"The girgol Subproject"},{"id": 5, "project_id": 3, "name": "plugh", "fullname": "The plugh Subproject"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -98,7 +98,7 @@ func subprojectsSubGetViewer(root string) *testresult.TestResult {
 
 	url := root + "/projects/2/subprojects"
 
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"}]}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
 		return res
@@ -126,7 +126,7 @@ func subprojectsSubPostOperator(root string) *testresult.TestResult {
 
 	// first, send POST to add a new subproject
 	body := `{"name": "plugh", "fullname": "The plugh Subproject"}`
-	res.Wanted = `{"id": 5}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Post(res, "1", url, body, 201, "operator")
 	if err != nil {
 		return res
@@ -139,7 +139,7 @@ func subprojectsSubPostOperator(root string) *testresult.TestResult {
 
 	// now, confirm that a new subproject was actually added
 	url = root + "/subprojects"
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"},{"id":4,"project_id":3,"name":"girgol","fullname":"The girgol Subproject"},{"id": 5, "project_id": 2, "name": "plugh", "fullname": "The plugh Subproject"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -163,7 +163,7 @@ func subprojectsGetOneViewer(root string) *testresult.TestResult {
 		ID:      "GET (viewer)",
 	}
 
-	res.Wanted = `{"subproject":{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"}}`
+	res.Wanted = utils.Golden(res, "1")
 	url := root + "/subprojects/2"
 	err := utils.GetContent(res, "1", url, 200, "viewer")
 	if err != nil {
@@ -204,7 +204,7 @@ func subprojectsPutOneOperator(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the subproject was actually updated
-	res.Wanted = `{"subproject":{"id":2,"project_id":2,"name":"plugh","fullname":"The plugh Subproject"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -229,7 +229,7 @@ func subprojectsPutOneViewer(root string) *testresult.TestResult {
 	url := root + "/subprojects/2"
 
 	body := `{"name": "plugh", "fullname": "The plugh Subproject"}`
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Put(res, "1", url, body, 403, "viewer")
 	if err != nil {
 		return res
@@ -241,7 +241,7 @@ func subprojectsPutOneViewer(root string) *testresult.TestResult {
 	}
 
 	// now, confirm that the subproject was NOT actually updated
-	res.Wanted = `{"subproject":{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"}}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", url, 200, "operator")
 	if err != nil {
 		return res
@@ -281,7 +281,7 @@ func subprojectsDeleteOneAdmin(root string) *testresult.TestResult {
 
 	// now, confirm that the subproject is gone
 	allURL := root + "/subprojects"
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"},{"id":4,"project_id":3,"name":"girgol","fullname":"The girgol Subproject"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -306,7 +306,7 @@ func subprojectsDeleteOneOperator(root string) *testresult.TestResult {
 	url := root + "/subprojects/2"
 
 	// try and fail to delete the subproject
-	res.Wanted = `{"error": "Access denied"}`
+	res.Wanted = utils.Golden(res, "1")
 	err := utils.Delete(res, "1", url, ``, 403, "operator")
 	if err != nil {
 		return res
@@ -319,7 +319,7 @@ func subprojectsDeleteOneOperator(root string) *testresult.TestResult {
 
 	// now, confirm that the subproject has NOT been deleted
 	allURL := root + "/subprojects"
-	res.Wanted = `{"subprojects":[{"id":1,"project_id":2,"name":"blorple","fullname":"The blorple Subproject"},{"id":2,"project_id":2,"name":"filfre","fullname":"The filfre Subproject"},{"id":3,"project_id":2,"name":"fweep","fullname":"The fweep Subproject"},{"id":4,"project_id":3,"name":"girgol","fullname":"The girgol Subproject"}]}`
+	res.Wanted = utils.Golden(res, "3")
 	err = utils.GetContent(res, "3", allURL, 200, "viewer")
 	if err != nil {
 		return res
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package errcontract triggers each class of error on every
+// resource, and checks that the API reports them all the same way:
+// with the same status code, in a JSON object with an "error"
+// string, and without leaking internal details.
+package errcontract
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// resource holds the calls that trigger each error class for one
+// type of object. An empty path means the class does not apply.
+type resource struct {
+	element string
+
+	// item is an object that exists in the fixture.
+	item string
+
+	// missing is an object that does not exist.
+	missing string
+
+	// badID is an object path with an ID that is not a number.
+	badID string
+
+	// create is the path to POST new objects to, with a body that
+	// would succeed and one that is valid JSON but fails
+	// validation.
+	create      string
+	validBody   string
+	invalidBody string
+}
+
+var resources = []*resource{
+	{
+		element:     "users",
+		item:        "/users/2",
+		missing:     "/users/999",
+		badID:       "/users/abc",
+		create:      "/users",
+		validBody:   `{"name": "New User", "github": "newuser", "access": "viewer"}`,
+		invalidBody: `{"name": "New User", "github": "newuser", "access": "superuser"}`,
+	},
+	{
+		element:     "projects",
+		item:        "/projects/2",
+		missing:     "/projects/999",
+		badID:       "/projects/abc",
+		create:      "/projects",
+		validBody:   `{"name": "inform", "fullname": "The inform Project"}`,
+		invalidBody: `{"fullname": "No name"}`,
+	},
+	{
+		element:     "subprojects",
+		item:        "/subprojects/2",
+		missing:     "/subprojects/999",
+		badID:       "/subprojects/abc",
+		create:      "/subprojects",
+		validBody:   `{"project_id": 2, "name": "zork", "fullname": "The zork Subproject"}`,
+		invalidBody: `{"project_id": 999, "name": "zork", "fullname": "The zork Subproject"}`,
+	},
+	{
+		element:     "repos",
+		item:        "/repos/2",
+		missing:     "/repos/999",
+		badID:       "/repos/abc",
+		create:      "/repos",
+		validBody:   `{"subproject_id": 2, "name": "filfre-web", "address": "https://example.com/filfre-web.git"}`,
+		invalidBody: `{"subproject_id": 999, "name": "filfre-web", "address": "https://example.com/filfre-web.git"}`,
+	},
+	{
+		element:     "repos/{id}/branches",
+		item:        "/repos/2/branches",
+		missing:     "/repos/999/branches",
+		badID:       "/repos/abc/branches",
+		create:      "/repos/2/branches",
+		validBody:   `{"branch": "feature"}`,
+		invalidBody: `{"branch": ""}`,
+	},
+	{
+		element:     "repos/{id}/branches/{branch}",
+		item:        "/repos/2/branches/dev-2.1",
+		missing:     "/repos/2/branches/no-such-branch",
+		badID:       "/repos/abc/branches/dev-2.1",
+		create:      "/repos/2/branches/dev-2.1",
+		validBody:   `{"commit": "2233b1da4a64aaf7587ffa78803922337864e74c"}`,
+		invalidBody: `{"commit": "not-a-commit"}`,
+	},
+	{
+		element: "repopulls",
+		item:    "/repopulls/4",
+		missing: "/repopulls/999",
+		badID:   "/repopulls/abc",
+	},
+	{
+		element:     "repopulls/{id}/jobs",
+		item:        "/repopulls/4/jobs",
+		missing:     "/repopulls/999/jobs",
+		badID:       "/repopulls/abc/jobs",
+		create:      "/repopulls/4/jobs",
+		validBody:   `{"agent_id": 1, "priorjob_ids": [], "is_ready": false, "config": {}}`,
+		invalidBody: `{"agent_id": 999, "priorjob_ids": [], "is_ready": false, "config": {}}`,
+	},
+	{
+		element: "jobs",
+		item:    "/jobs/4",
+		missing: "/jobs/999",
+		badID:   "/jobs/abc",
+	},
+	{
+		element:     "agents",
+		item:        "/agents/2",
+		missing:     "/agents/999",
+		badID:       "/agents/abc",
+		create:      "/agents",
+		validBody:   `{"name": "new-agent", "is_active": true, "address": "localhost", "port": 2099, "is_codereader": false, "is_spdxreader": true, "is_codewriter": false, "is_spdxwriter": false}`,
+		invalidBody: `{"name": "", "is_active": true, "address": "localhost", "port": -1}`,
+	},
+}
+
+// call is a single request that should produce an error.
+type call struct {
+	method string
+	path   string
+	body   string
+	user   string
+}
+
+// class is a kind of error, with the status code that every
+// resource should use for it.
+type class struct {
+	name string
+	code int
+
+	// call returns the request that triggers this class of error
+	// for a resource, or nil if the class does not apply.
+	call func(r *resource) *call
+}
+
+var classes = []*class{
+	{
+		name: "not found",
+		code: 404,
+		call: func(r *resource) *call {
+			return &call{"GET", r.missing, "", "admin"}
+		},
+	},
+	{
+		name: "invalid id",
+		code: 400,
+		call: func(r *resource) *call {
+			return &call{"GET", r.badID, "", "admin"}
+		},
+	},
+	{
+		name: "malformed body",
+		code: 400,
+		call: func(r *resource) *call {
+			if r.create == "" {
+				return nil
+			}
+			return &call{"POST", r.create, `{"name": `, "admin"}
+		},
+	},
+	{
+		name: "invalid body",
+		code: 400,
+		call: func(r *resource) *call {
+			if r.create == "" {
+				return nil
+			}
+			return &call{"POST", r.create, r.invalidBody, "admin"}
+		},
+	},
+	{
+		name: "unauthenticated",
+		code: 401,
+		call: func(r *resource) *call {
+			return &call{"GET", r.item, "", "none"}
+		},
+	},
+	{
+		name: "forbidden",
+		code: 403,
+		call: func(r *resource) *call {
+			if r.create == "" {
+				return nil
+			}
+			return &call{"POST", r.create, r.validBody, "viewer"}
+		},
+	},
+}
+
+// GetTests returns all of the error contract test suites.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	for _, c := range classes {
+		for _, r := range resources {
+			if c.call(r) != nil {
+				allTests = append(allTests, errorTest(c, r))
+			}
+		}
+		allTests = append(allTests, consistencyTest(c))
+	}
+
+	return allTests
+}
+
+// checkEnvelope returns an error unless b is a JSON object with a
+// non-empty "error" string and no internal details.
+func checkEnvelope(b []byte) error {
+	var obj map[string]interface{}
+	err := json.Unmarshal(b, &obj)
+	if err != nil {
+		return fmt.Errorf("expected a JSON object, got %q", string(b))
+	}
+	msg, ok := obj["error"].(string)
+	if !ok || msg == "" {
+		return fmt.Errorf(`expected a non-empty "error" string, got %q`, string(b))
+	}
+	if m := utils.FindLeak(b); m != "" {
+		return fmt.Errorf("error message contains %q, which looks like a leak: %q", m, msg)
+	}
+	return nil
+}
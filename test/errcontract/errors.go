@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package errcontract
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// errorTest returns a test that triggers one class of error on one
+// resource, and checks the status code and the error envelope.
+func errorTest(c *class, r *resource) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		cl := c.call(r)
+		res := &testresult.TestResult{
+			Suite:   "errcontract",
+			Element: r.element,
+			ID:      fmt.Sprintf("%s (%s)", cl.method, c.name),
+		}
+
+		code, b, err := utils.Send(res, "1", cl.method, root+cl.path, cl.body, cl.user)
+		res.Got = b
+		if err != nil {
+			utils.FailTest(res, "1", err)
+			return res
+		}
+		if code != c.code {
+			utils.FailTest(res, "1", fmt.Errorf("expected status code %d, got %d", c.code, code))
+			return res
+		}
+
+		err = checkEnvelope(b)
+		if err != nil {
+			utils.FailTest(res, "2", err)
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// consistencyTest returns a test that triggers one class of error
+// on every resource, and checks that they all use the same status
+// code, whatever it is. This shows the spread of codes when the
+// individual tests fail.
+func consistencyTest(c *class) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "errcontract",
+			Element: "all",
+			ID:      c.name + " (consistency)",
+		}
+
+		byCode := map[int][]string{}
+		for _, r := range resources {
+			cl := c.call(r)
+			if cl == nil {
+				continue
+			}
+			code, _, err := utils.Send(res, "1", cl.method, root+cl.path, cl.body, cl.user)
+			if err != nil {
+				utils.FailTest(res, "1", fmt.Errorf("%s: %v", r.element, err))
+				return res
+			}
+			byCode[code] = append(byCode[code], r.element)
+		}
+
+		if len(byCode) > 1 {
+			codes := []int{}
+			for code := range byCode {
+				codes = append(codes, code)
+			}
+			sort.Ints(codes)
+			spread := []string{}
+			for _, code := range codes {
+				spread = append(spread, fmt.Sprintf("%d for %s", code, strings.Join(byCode[code], ", ")))
+			}
+			utils.FailTest(res, "2", fmt.Errorf("resources disagree on the status code: %s", strings.Join(spread, "; ")))
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
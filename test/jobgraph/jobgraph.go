@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package jobgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// GetTests returns all of the job dependency graph test suites.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	for _, c := range invalidJobCases {
+		allTests = append(allTests, invalidJobTest(c))
+	}
+	allTests = append(allTests,
+		jobsDeletedPriorRejected,
+		jobsCannotFormCycle,
+		jobsNotStartedBeforePriors,
+	)
+
+	return allTests
+}
+
+// listJobIDs returns the sorted IDs of the jobs for a repopull.
+func listJobIDs(res *testresult.TestResult, step string, root string, repoPullID uint32) ([]uint32, error) {
+	url := fmt.Sprintf("%s/repopulls/%d/jobs", root, repoPullID)
+	err := utils.GetContent(res, step, url, 200, "viewer")
+	if err != nil {
+		return nil, err
+	}
+
+	var list struct {
+		Jobs []struct {
+			ID uint32 `json:"id"`
+		} `json:"jobs"`
+	}
+	err = json.Unmarshal(res.Got, &list)
+	if err != nil {
+		utils.FailTest(res, step, err)
+		return nil, err
+	}
+
+	ids := []uint32{}
+	for _, j := range list.Jobs {
+		ids = append(ids, j.ID)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+// confirmJobIDs checks that the repopull has exactly the wanted
+// jobs, which must be given in ascending order.
+func confirmJobIDs(res *testresult.TestResult, step string, root string, repoPullID uint32, want []uint32) error {
+	got, err := listJobIDs(res, step, root, repoPullID)
+	if err != nil {
+		return err
+	}
+
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		res.Wanted = fmt.Sprintf("job IDs %v", want)
+		err = fmt.Errorf("expected repopull %d to have jobs %v, got %v", repoPullID, want, got)
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	return nil
+}
+
+// postRejected sends a POST that should be rejected as invalid,
+// and checks that it returns a 400 with an error message.
+func postRejected(res *testresult.TestResult, step string, url string, body string) error {
+	err := utils.Post(res, step, url, body, 400, "operator")
+	if err != nil {
+		return err
+	}
+
+	if !utils.IsError(res) {
+		err = fmt.Errorf("expected error message in response")
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package jobgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// invalidJobCase describes a new job whose prior jobs or config
+// refer to jobs that it cannot depend on.
+type invalidJobCase struct {
+	// id identifies the test within the element.
+	id string
+
+	// repoPullID is the repopull to create the job on.
+	repoPullID uint32
+
+	// body is the body of the POST request.
+	body string
+
+	// existing is the sorted list of job IDs that the repopull
+	// has in the fixture, and should still have afterwards.
+	existing []uint32
+}
+
+// In the fixture, repopull 3 has no jobs, and repopull 4 has jobs
+// 2, 3 and 4, with 3 depending on 2 and 4 depending on 2 and 3.
+// Job 1 is on repopull 2. The next job created will be job 5.
+var invalidJobCases = []invalidJobCase{
+	{
+		id:         "POST (self-reference)",
+		repoPullID: 3,
+		body:       `{"agent_id": 1, "priorjob_ids": [5], "is_ready": false, "config": {}}`,
+		existing:   []uint32{},
+	},
+	{
+		id:         "POST (forward reference)",
+		repoPullID: 3,
+		body:       `{"agent_id": 1, "priorjob_ids": [6], "is_ready": false, "config": {}}`,
+		existing:   []uint32{},
+	},
+	{
+		id:         "POST (other repopull)",
+		repoPullID: 3,
+		body:       `{"agent_id": 1, "priorjob_ids": [2], "is_ready": false, "config": {}}`,
+		existing:   []uint32{},
+	},
+	{
+		id:         "POST (mixed repopulls)",
+		repoPullID: 4,
+		body:       `{"agent_id": 1, "priorjob_ids": [1, 2], "is_ready": false, "config": {}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+	{
+		id:         "POST (nonexistent)",
+		repoPullID: 4,
+		body:       `{"agent_id": 1, "priorjob_ids": [2, 99], "is_ready": false, "config": {}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+	{
+		id:         "POST (duplicate)",
+		repoPullID: 4,
+		body:       `{"agent_id": 1, "priorjob_ids": [2, 2], "is_ready": false, "config": {}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+	{
+		id:         "POST (config priorjob_id not in priorjob_ids)",
+		repoPullID: 4,
+		body:       `{"agent_id": 2, "priorjob_ids": [2], "is_ready": false, "config": {"codereader": {"godeps": {"priorjob_id": 3}}}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+	{
+		id:         "POST (config priorjob_id on other repopull)",
+		repoPullID: 4,
+		body:       `{"agent_id": 4, "priorjob_ids": [2], "is_ready": false, "config": {"spdxreader": {"godeps": {"priorjob_id": 1}}}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+	{
+		id:         "POST (config priorjob_id self-reference)",
+		repoPullID: 4,
+		body:       `{"agent_id": 4, "priorjob_ids": [2], "is_ready": false, "config": {"spdxreader": {"godeps": {"priorjob_id": 5}}}}`,
+		existing:   []uint32{2, 3, 4},
+	},
+}
+
+// invalidJobTest returns a test that tries and fails to create
+// the job described by c, and then confirms that no job was added.
+func invalidJobTest(c invalidJobCase) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "jobgraph",
+			Element: "repopulls/{id}/jobs",
+			ID:      c.id,
+		}
+
+		url := fmt.Sprintf("%s/repopulls/%d/jobs", root, c.repoPullID)
+
+		// first, try and fail to create the job
+		err := postRejected(res, "1", url, c.body)
+		if err != nil {
+			return res
+		}
+
+		// now, confirm that no job was added to the repopull
+		err = confirmJobIDs(res, "2", root, c.repoPullID, c.existing)
+		if err != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== prior job that has been deleted
+
+func jobsDeletedPriorRejected(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "jobgraph",
+		Element: "repopulls/{id}/jobs",
+		ID:      "POST (deleted prior)",
+	}
+
+	// first, delete job 3; this is the job that job 4 depends on
+	res.Wanted = ``
+	err := utils.Delete(res, "1", root+"/jobs/3", ``, 204, "admin")
+	if err != nil {
+		return res
+	}
+
+	// now, try and fail to create a job that depends on it
+	url := root + "/repopulls/4/jobs"
+	body := `{"agent_id": 2, "priorjob_ids": [2, 3], "is_ready": false, "config": {}}`
+	err = postRejected(res, "2", url, body)
+	if err != nil {
+		return res
+	}
+
+	// and confirm that no job was added
+	err = confirmJobIDs(res, "3", root, 4, []uint32{2, 4})
+	if err != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== cycles
+
+func jobsCannotFormCycle(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "jobgraph",
+		Element: "jobs/{id}",
+		ID:      "PUT (cycle)",
+	}
+
+	// jobs can only depend on jobs that already exist, so the
+	// only way to form a cycle is to change the prior jobs of
+	// an existing job. Job 4 depends on job 3, so try to make
+	// job 3 depend on job 4. The API may reject the change, or
+	// ignore priorjob_ids on update, but must not store it.
+	url := root + "/jobs/3"
+	body := `{"priorjob_ids": [2, 4]}`
+	code, b, err := utils.Send(res, "1", "PUT", url, body, "operator")
+	if err != nil {
+		utils.FailTest(res, "1", err)
+		return res
+	}
+	res.Got = b
+	if code != 204 && code != 400 {
+		utils.FailTest(res, "1", fmt.Errorf("expected HTTP status code 204 or 400, got %d", code))
+		return res
+	}
+
+	// now, confirm that job 3 still only depends on job 2
+	res.Wanted = utils.Golden(res, "2")
+	err = utils.GetContent(res, "2", url, 200, "viewer")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "3")
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== readiness vs. completion of prior jobs
+
+func jobsNotStartedBeforePriors(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "jobgraph",
+		Element: "jobs/{id}",
+		ID:      "ready before priors complete",
+	}
+
+	// job 4 depends on jobs 2 and 3, neither of which has run.
+	// Marking it ready must not let it start ahead of them.
+	url := root + "/jobs/4"
+	res.Wanted = ``
+	err := utils.Put(res, "1", url, `{"is_ready": true}`, 204, "operator")
+	if err != nil {
+		return res
+	}
+
+	// give the controller a chance to (wrongly) start the job
+	time.Sleep(2 * time.Second)
+
+	err = utils.GetContent(res, "2", url, 200, "viewer")
+	if err != nil {
+		return res
+	}
+
+	var wrapper struct {
+		Job struct {
+			Status    string    `json:"status"`
+			StartedAt time.Time `json:"started_at"`
+			IsReady   bool      `json:"is_ready"`
+		} `json:"job"`
+	}
+	err = json.Unmarshal(res.Got, &wrapper)
+	if err != nil {
+		utils.FailTest(res, "3", err)
+		return res
+	}
+
+	j := wrapper.Job
+	if !j.IsReady {
+		utils.FailTest(res, "3", fmt.Errorf("expected is_ready to be true after update"))
+		return res
+	}
+	if j.Status != "startup" || !j.StartedAt.IsZero() {
+		utils.FailTest(res, "3", fmt.Errorf("job started (status %q, started_at %v) before its prior jobs completed", j.Status, j.StartedAt))
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package privesc tries to raise or take over privileges through
+// the users endpoints, as each role, and confirms through admin
+// GETs that no user's access or identity changed.
+package privesc
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/client"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// fixtureUsers are the users that the fixture creates, by ID.
+var fixtureUsers = map[uint32]client.User{
+	1: {ID: 1, Name: "Admin", Github: "admin", Access: "admin"},
+	2: {ID: 2, Name: "Operator User", Github: "operator", Access: "operator"},
+	3: {ID: 3, Name: "Commenter User", Github: "commenter", Access: "commenter"},
+	4: {ID: 4, Name: "Viewer User", Github: "viewer", Access: "viewer"},
+	5: {ID: 5, Name: "Disabled User", Github: "disabled", Access: "disabled"},
+}
+
+// userIDs maps each role to the ID of the fixture user with it.
+var userIDs = map[string]uint32{
+	"admin":     1,
+	"operator":  2,
+	"commenter": 3,
+	"viewer":    4,
+	"disabled":  5,
+}
+
+// nonAdmins are the roles that should never be able to change
+// anyone's access.
+var nonAdmins = []string{"operator", "commenter", "viewer", "disabled"}
+
+// GetTests returns all of the privilege escalation test suites.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	for _, a := range attempts {
+		for _, role := range a.roles {
+			allTests = append(allTests, attemptTest(a, role))
+		}
+	}
+
+	return allTests
+}
+
+// confirmUsers checks, as admin, that every fixture user still has
+// its original github and access, and that no other user has been
+// given admin access.
+func confirmUsers(res *testresult.TestResult, step string, root string) error {
+	c := client.New(root, utils.Identity("admin"))
+	users, err := c.ListUsers()
+	if err != nil {
+		err = fmt.Errorf("could not list users as admin: %v", err)
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	seen := map[uint32]bool{}
+	for _, u := range users {
+		want, ok := fixtureUsers[u.ID]
+		if !ok {
+			if u.Access == "admin" {
+				err = fmt.Errorf("new user %d (%s) has admin access", u.ID, u.Github)
+				utils.FailTest(res, step, err)
+				return err
+			}
+			continue
+		}
+		seen[u.ID] = true
+		if u.Github != want.Github || u.Access != want.Access {
+			err = fmt.Errorf("user %d is now github %q, access %q; wanted github %q, access %q", u.ID, u.Github, u.Access, want.Github, want.Access)
+			utils.FailTest(res, step, err)
+			return err
+		}
+	}
+	for id := range fixtureUsers {
+		if !seen[id] {
+			err = fmt.Errorf("user %d is missing", id)
+			utils.FailTest(res, step, err)
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package privesc
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// attempt is a call that tries to change privileges it should not
+// be able to.
+type attempt struct {
+	id     string
+	method string
+
+	// target returns the ID of the user to send the call to, given
+	// the caller's own ID, or 0 to POST a new user.
+	target func(self uint32) uint32
+
+	body  string
+	roles []string
+
+	// mayAccept is true if the API may accept the call, as long as
+	// it ignores the fields that would change privileges.
+	mayAccept bool
+}
+
+func self(id uint32) uint32 { return id }
+
+func user(id uint32) func(uint32) uint32 {
+	return func(uint32) uint32 { return id }
+}
+
+func newUser(uint32) uint32 { return 0 }
+
+var attempts = []attempt{
+	// ===== raising own access
+	{
+		id:     "PUT own access admin",
+		method: "PUT",
+		target: self,
+		body:   `{"access": "admin"}`,
+		roles:  nonAdmins,
+	},
+	{
+		id:     "PUT own access operator",
+		method: "PUT",
+		target: self,
+		body:   `{"access": "operator"}`,
+		roles:  []string{"commenter", "viewer", "disabled"},
+	},
+	{
+		id:        "PUT own name and access admin",
+		method:    "PUT",
+		target:    self,
+		body:      `{"name": "Still Me", "access": "admin"}`,
+		roles:     []string{"operator", "commenter", "viewer"},
+		mayAccept: true,
+	},
+
+	// ===== disabled user re-enabling themselves
+	{
+		id:     "PUT own access viewer",
+		method: "PUT",
+		target: self,
+		body:   `{"access": "viewer"}`,
+		roles:  []string{"disabled"},
+	},
+	{
+		id:     "PUT own name",
+		method: "PUT",
+		target: self,
+		body:   `{"name": "Not Disabled"}`,
+		roles:  []string{"disabled"},
+	},
+
+	// ===== taking over another identity
+	{
+		id:     "PUT own github admin",
+		method: "PUT",
+		target: self,
+		body:   `{"github": "admin"}`,
+		roles:  nonAdmins,
+	},
+	{
+		id:     "PUT other github",
+		method: "PUT",
+		target: user(4),
+		body:   `{"github": "admin"}`,
+		roles:  []string{"admin", "operator", "commenter", "disabled"},
+	},
+	{
+		id:     "PUT other github to own",
+		method: "PUT",
+		target: user(1),
+		body:   `{"github": "attacker"}`,
+		roles:  nonAdmins,
+	},
+
+	// ===== changing others' access
+	{
+		id:     "PUT admin access disabled",
+		method: "PUT",
+		target: user(1),
+		body:   `{"access": "disabled"}`,
+		roles:  nonAdmins,
+	},
+	{
+		id:     "PUT other access admin",
+		method: "PUT",
+		target: user(5),
+		body:   `{"access": "admin"}`,
+		roles:  []string{"operator", "commenter", "viewer"},
+	},
+	{
+		id:     "POST new admin",
+		method: "POST",
+		target: newUser,
+		body:   `{"name": "Sneaky", "github": "sneaky", "access": "admin"}`,
+		roles:  nonAdmins,
+	},
+
+	// ===== demoting the last admin
+	{
+		id:     "PUT last admin access operator",
+		method: "PUT",
+		target: user(1),
+		body:   `{"access": "operator"}`,
+		roles:  []string{"admin"},
+	},
+	{
+		id:     "PUT last admin access disabled",
+		method: "PUT",
+		target: user(1),
+		body:   `{"access": "disabled"}`,
+		roles:  []string{"admin"},
+	},
+}
+
+// attemptTest returns a test that makes the attempt as the given
+// role, checks that it is rejected, and confirms that no user's
+// privileges changed.
+func attemptTest(a attempt, role string) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "privesc",
+			Element: "users/{id}",
+			ID:      fmt.Sprintf("%s (%s)", a.id, role),
+		}
+
+		url := root + "/users"
+		if id := a.target(userIDs[role]); id != 0 {
+			url = fmt.Sprintf("%s/users/%d", root, id)
+		}
+		if a.method == "POST" {
+			res.Element = "users"
+		}
+
+		// first, make the attempt
+		code, b, err := utils.Send(res, "1", a.method, url, a.body, role)
+		res.Got = b
+		if err != nil {
+			utils.FailTest(res, "1", err)
+			return res
+		}
+		accepted := code >= 200 && code < 300
+		rejected := code >= 400 && code < 500
+		if !rejected && !(accepted && a.mayAccept) {
+			utils.FailTest(res, "1", fmt.Errorf("expected the call to be rejected with a 4xx code, got %d", code))
+			return res
+		}
+		if rejected && !utils.IsError(res) {
+			utils.FailTest(res, "2", fmt.Errorf("expected an error message with code %d", code))
+			return res
+		}
+
+		// now, confirm as admin that nothing changed
+		err = confirmUsers(res, "3", root)
+		if err != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
@@ -0,0 +1,257 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package protocol checks the API's HTTP behavior apart from the
+// content of its responses: which methods each route allows, how
+// it treats request content types, and the headers it sends back.
+// The expected behavior is read from a policy file.
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// PolicyFile is the path to the JSON file holding the expected
+// protocol behavior.
+var PolicyFile = "test/protocol/policy.json"
+
+// policy is the expected protocol behavior of the API.
+type policy struct {
+	// UnsupportedCode is the status code for a method that a
+	// route does not support.
+	UnsupportedCode int `json:"unsupported_code"`
+
+	// AllowHeader is true if responses with UnsupportedCode, and
+	// responses to OPTIONS, must list the supported methods in an
+	// Allow header.
+	AllowHeader bool `json:"allow_header"`
+
+	// BadContentTypeCodes are the acceptable status codes for a
+	// body sent with a content type other than JSON.
+	BadContentTypeCodes []int `json:"bad_content_type_codes"`
+
+	// MissingBodyCodes are the acceptable status codes for a POST
+	// or PUT with no body.
+	MissingBodyCodes []int `json:"missing_body_codes"`
+
+	// JSONContentType is the media type that every response with
+	// a body must have.
+	JSONContentType string `json:"json_content_type"`
+
+	// HeadSupported is true if HEAD must work wherever GET does.
+	HeadSupported bool `json:"head_supported"`
+
+	// OptionsCodes are the acceptable status codes for OPTIONS.
+	OptionsCodes []int `json:"options_codes"`
+
+	CORS corsPolicy `json:"cors"`
+
+	Routes []*route `json:"routes"`
+}
+
+// corsPolicy is the expected cross-origin behavior.
+type corsPolicy struct {
+	// Origin is sent in the Origin header of CORS requests.
+	Origin string `json:"origin"`
+
+	// AllowOrigin is the expected Access-Control-Allow-Origin
+	// header for requests from Origin, or "" if the header should
+	// not be sent at all.
+	AllowOrigin string `json:"allow_origin"`
+}
+
+// route is one route template, with a concrete path in the fixture
+// to test it with.
+type route struct {
+	Template string   `json:"template"`
+	Path     string   `json:"path"`
+	Methods  []string `json:"methods"`
+
+	// Bodies holds a valid request body for each method that
+	// takes one.
+	Bodies map[string]json.RawMessage `json:"bodies"`
+}
+
+// allows reports whether the route supports a method.
+func (r *route) allows(method string) bool {
+	for _, m := range r.Methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// allMethods are the methods checked against every route.
+var allMethods = []string{"GET", "POST", "PUT", "DELETE", "PATCH"}
+
+// GetTests returns all of the protocol test suites.
+func GetTests() []testresult.TestFunc {
+	p, err := readPolicy(PolicyFile)
+	if err != nil {
+		return []testresult.TestFunc{policyError(err)}
+	}
+
+	allTests := []testresult.TestFunc{}
+	for _, r := range p.Routes {
+		allTests = append(allTests, unsupportedMethodsTest(p, r))
+		for _, m := range r.Methods {
+			if _, ok := r.Bodies[m]; ok {
+				allTests = append(allTests, contentTypeTest(p, r, m))
+			}
+		}
+		if r.allows("GET") {
+			allTests = append(allTests, getHeadersTest(p, r))
+			allTests = append(allTests, headTest(p, r))
+		}
+		allTests = append(allTests, optionsTest(p, r))
+	}
+	return allTests
+}
+
+// readPolicy reads the policy from a file.
+func readPolicy(filename string) (*policy, error) {
+	b, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var p policy
+	err = json.Unmarshal(b, &p)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	return &p, nil
+}
+
+// policyError returns a test that always fails because the policy
+// could not be read, so that the problem is reported alongside the
+// other results.
+func policyError(err error) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: "policy",
+			ID:      "read",
+		}
+
+		utils.FailTest(res, "0", err)
+		return res
+	}
+}
+
+// send makes a call as admin with the given body, content type and
+// extra headers, records it in res's transcript as step, and
+// returns the response with its body read.
+func send(res *testresult.TestResult, step string, method string, url string, body string, contentType string, headers map[string]string) (*http.Response, []byte, error) {
+	resp, b, err := sendRequest(method, url, body, contentType, headers)
+	code := 0
+	if resp != nil {
+		code = resp.StatusCode
+	}
+	utils.Record(res, step, method, url, "admin", body, code, b, err)
+	return resp, b, err
+}
+
+func sendRequest(method string, url string, body string, contentType string, headers map[string]string) (*http.Response, []byte, error) {
+	var rd io.Reader
+	if body != "" {
+		rd = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, url, rd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	utils.AddAuthHeader(nil, "0", req, "admin")
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp, nil, err
+	}
+	return resp, b, nil
+}
+
+// checkCode returns an error unless code is one of want.
+func checkCode(code int, want ...int) error {
+	for _, w := range want {
+		if code == w {
+			return nil
+		}
+	}
+	if len(want) == 1 {
+		return fmt.Errorf("expected status code %d, got %d", want[0], code)
+	}
+	return fmt.Errorf("expected status code in %v, got %d", want, code)
+}
+
+// checkJSON returns an error if a response has a body, but not the
+// JSON content type.
+func checkJSON(p *policy, resp *http.Response, body []byte) error {
+	if len(body) == 0 {
+		return nil
+	}
+	ct := resp.Header.Get("Content-Type")
+	mediaType := strings.TrimSpace(strings.Split(ct, ";")[0])
+	if !strings.EqualFold(mediaType, p.JSONContentType) {
+		return fmt.Errorf("expected Content-Type %s, got %q", p.JSONContentType, ct)
+	}
+	return nil
+}
+
+// checkAllow returns an error unless the Allow header lists exactly
+// the route's methods, apart from HEAD and OPTIONS.
+func checkAllow(r *route, resp *http.Response) error {
+	got := map[string]bool{}
+	for _, h := range resp.Header["Allow"] {
+		for _, m := range strings.Split(h, ",") {
+			m = strings.ToUpper(strings.TrimSpace(m))
+			if m != "" && m != "HEAD" && m != "OPTIONS" {
+				got[m] = true
+			}
+		}
+	}
+
+	ok := len(got) == len(r.Methods)
+	for _, m := range r.Methods {
+		ok = ok && got[m]
+	}
+	if !ok {
+		return fmt.Errorf("expected Allow header listing %v, got %q", r.Methods, resp.Header.Get("Allow"))
+	}
+	return nil
+}
+
+// checkAllowOrigin returns an error unless the response has the
+// expected Access-Control-Allow-Origin header.
+func checkAllowOrigin(p *policy, resp *http.Response) error {
+	got := resp.Header.Get("Access-Control-Allow-Origin")
+	if got != p.CORS.AllowOrigin {
+		if p.CORS.AllowOrigin == "" {
+			return fmt.Errorf("expected no Access-Control-Allow-Origin header, got %q", got)
+		}
+		return fmt.Errorf("expected Access-Control-Allow-Origin %q, got %q", p.CORS.AllowOrigin, got)
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// fail records a failure at step and returns true if err is not
+// nil.
+func fail(res *testresult.TestResult, step string, err error) bool {
+	if err != nil {
+		utils.FailTest(res, step, err)
+		return true
+	}
+	return false
+}
+
+// ===== unsupported methods
+
+// unsupportedMethodsTest returns a test that sends every method
+// that the route does not support, and checks that each is refused
+// with the policy's code and an Allow header.
+func unsupportedMethodsTest(p *policy, r *route) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: r.Template,
+			ID:      "unsupported methods",
+		}
+
+		step := 0
+		for _, m := range allMethods {
+			if r.allows(m) {
+				continue
+			}
+			step++
+			s := fmt.Sprintf("%d", step)
+
+			resp, b, err := send(res, s, m, root+r.Path, "", "", nil)
+			res.Got = b
+			if fail(res, s, err) {
+				return res
+			}
+			if fail(res, s, wrap(m, checkCode(resp.StatusCode, p.UnsupportedCode))) {
+				return res
+			}
+			if p.AllowHeader && fail(res, s, wrap(m, checkAllow(r, resp))) {
+				return res
+			}
+			if fail(res, s, wrap(m, checkJSON(p, resp, b))) {
+				return res
+			}
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== request content types
+
+// contentTypeTest returns a test that sends the route's valid body
+// for a method, but labelled as plain text, and then sends no body
+// at all, and checks that both are rejected.
+func contentTypeTest(p *policy, r *route, method string) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: r.Template,
+			ID:      method + " (content type)",
+		}
+
+		// first, send a JSON body labelled as something else
+		resp, b, err := send(res, "1", method, root+r.Path, string(r.Bodies[method]), "text/plain", nil)
+		res.Got = b
+		if fail(res, "1", err) {
+			return res
+		}
+		if fail(res, "1", wrap("text/plain body", checkCode(resp.StatusCode, p.BadContentTypeCodes...))) {
+			return res
+		}
+		if fail(res, "2", checkJSON(p, resp, b)) {
+			return res
+		}
+
+		// now, send no body at all
+		resp, b, err = send(res, "3", method, root+r.Path, "", "application/json", nil)
+		res.Got = b
+		if fail(res, "3", err) {
+			return res
+		}
+		if fail(res, "3", wrap("missing body", checkCode(resp.StatusCode, p.MissingBodyCodes...))) {
+			return res
+		}
+		if fail(res, "4", checkJSON(p, resp, b)) {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET response headers
+
+// getHeadersTest returns a test that checks the headers of a
+// successful GET, both with and without a CORS Origin header.
+func getHeadersTest(p *policy, r *route) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: r.Template,
+			ID:      "GET (headers)",
+		}
+
+		resp, b, err := send(res, "1", "GET", root+r.Path, "", "", nil)
+		res.Got = b
+		if fail(res, "1", err) {
+			return res
+		}
+		if fail(res, "1", checkCode(resp.StatusCode, 200)) {
+			return res
+		}
+		if fail(res, "2", checkJSON(p, resp, b)) {
+			return res
+		}
+
+		// the same request from another origin
+		resp, b, err = send(res, "3", "GET", root+r.Path, "", "", map[string]string{"Origin": p.CORS.Origin})
+		res.Got = b
+		if fail(res, "3", err) {
+			return res
+		}
+		if fail(res, "3", checkCode(resp.StatusCode, 200)) {
+			return res
+		}
+		if fail(res, "4", checkAllowOrigin(p, resp)) {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== HEAD
+
+// headTest returns a test that checks HEAD against the policy: if
+// supported, it must succeed with no body and the same content type
+// as GET; if not, it must be refused like any unsupported method.
+func headTest(p *policy, r *route) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: r.Template,
+			ID:      "HEAD",
+		}
+
+		resp, b, err := send(res, "1", "HEAD", root+r.Path, "", "", nil)
+		res.Got = b
+		if fail(res, "1", err) {
+			return res
+		}
+
+		if !p.HeadSupported {
+			if fail(res, "1", checkCode(resp.StatusCode, p.UnsupportedCode)) {
+				return res
+			}
+			utils.Pass(res)
+			return res
+		}
+
+		if fail(res, "1", checkCode(resp.StatusCode, 200)) {
+			return res
+		}
+		if len(b) != 0 {
+			utils.FailTest(res, "2", fmt.Errorf("expected no body for HEAD, got %d bytes", len(b)))
+			return res
+		}
+
+		getResp, _, err := send(res, "3", "GET", root+r.Path, "", "", nil)
+		if fail(res, "3", err) {
+			return res
+		}
+		if resp.Header.Get("Content-Type") != getResp.Header.Get("Content-Type") {
+			utils.FailTest(res, "4", fmt.Errorf("expected Content-Type %q as for GET, got %q", getResp.Header.Get("Content-Type"), resp.Header.Get("Content-Type")))
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== OPTIONS and CORS preflight
+
+// optionsTest returns a test that sends a plain OPTIONS request and
+// then a CORS preflight for each supported method, and checks the
+// codes and headers against the policy.
+func optionsTest(p *policy, r *route) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "protocol",
+			Element: r.Template,
+			ID:      "OPTIONS",
+		}
+
+		resp, b, err := send(res, "1", "OPTIONS", root+r.Path, "", "", nil)
+		res.Got = b
+		if fail(res, "1", err) {
+			return res
+		}
+		if fail(res, "1", checkCode(resp.StatusCode, p.OptionsCodes...)) {
+			return res
+		}
+		if p.AllowHeader && fail(res, "2", checkAllow(r, resp)) {
+			return res
+		}
+
+		for i, m := range r.Methods {
+			s := fmt.Sprintf("%d", i+3)
+			resp, b, err = send(res, s, "OPTIONS", root+r.Path, "", "", map[string]string{
+				"Origin":                        p.CORS.Origin,
+				"Access-Control-Request-Method": m,
+			})
+			res.Got = b
+			if fail(res, s, err) {
+				return res
+			}
+			if fail(res, s, wrap("preflight "+m, checkCode(resp.StatusCode, p.OptionsCodes...))) {
+				return res
+			}
+			if fail(res, s, wrap("preflight "+m, checkAllowOrigin(p, resp))) {
+				return res
+			}
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// wrap adds context to a non-nil error.
+func wrap(context string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s: %v", context, err)
+}
@@ -0,0 +1,197 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package pulls
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Repos holds the local git repositories that the fixture's repos
+// point at. It must be set before the tests are run.
+var Repos *fixtures.GitRepos
+
+// Timeout is how long to wait for a repopull to finish.
+var Timeout = 60 * time.Second
+
+// pollInterval is how often to check a repopull while waiting.
+const pollInterval = 200 * time.Millisecond
+
+// GetTests returns all of the repopull test suites that need real
+// git repositories.
+func GetTests() []testresult.TestFunc {
+	return []testresult.TestFunc{
+		pullCommitFinishes,
+		pullTagFinishes,
+		pullBadCommitFails,
+		pullCommitOnOtherBranchFails,
+		pullBadBranchFails,
+		pullBadTagFails,
+		spdxStructure,
+		spdxVerificationCode,
+		spdxFileLicenses,
+	}
+}
+
+// repoPull holds the fields of a repopull that change as it is
+// pulled.
+type repoPull struct {
+	ID         uint32    `json:"id"`
+	Status     string    `json:"status"`
+	Health     string    `json:"health"`
+	Commit     string    `json:"commit"`
+	Tag        string    `json:"tag"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Output     string    `json:"output"`
+	SPDXID     string    `json:"spdx_id"`
+}
+
+// startPull sends a POST to create a repopull, and returns the new
+// repopull's ID.
+func startPull(res *testresult.TestResult, step string, root string, repoID uint32, branch string, body string) (uint32, error) {
+	url := fmt.Sprintf("%s/repos/%d/branches/%s", root, repoID, branch)
+	err := utils.Post(res, step, url, body, 201, "operator")
+	if err != nil {
+		return 0, err
+	}
+
+	var created struct {
+		ID uint32 `json:"id"`
+	}
+	err = json.Unmarshal(res.Got, &created)
+	if err != nil {
+		utils.FailTest(res, step, err)
+		return 0, err
+	}
+
+	return created.ID, nil
+}
+
+// getPull fetches the current state of a repopull.
+func getPull(res *testresult.TestResult, step string, root string, id uint32) (*repoPull, error) {
+	url := fmt.Sprintf("%s/repopulls/%d", root, id)
+	err := utils.GetContent(res, step, url, 200, "viewer")
+	if err != nil {
+		return nil, err
+	}
+
+	var wrapper struct {
+		RepoPull *repoPull `json:"repopull"`
+	}
+	err = json.Unmarshal(res.Got, &wrapper)
+	if err == nil && wrapper.RepoPull == nil {
+		err = fmt.Errorf("response has no repopull")
+	}
+	if err != nil {
+		utils.FailTest(res, step, err)
+		return nil, err
+	}
+
+	return wrapper.RepoPull, nil
+}
+
+// waitForPull polls the repopull until it has stopped, or until
+// Timeout has elapsed, and returns its final state.
+func waitForPull(res *testresult.TestResult, step string, root string, id uint32) (*repoPull, error) {
+	deadline := time.Now().Add(Timeout)
+	for {
+		rp, err := getPull(res, step, root, id)
+		if err != nil {
+			return nil, err
+		}
+		if rp.Status == "stopped" {
+			return rp, nil
+		}
+		if time.Now().After(deadline) {
+			err = fmt.Errorf("repopull %d did not stop within %v; last status %q, health %q", id, Timeout, rp.Status, rp.Health)
+			utils.FailTest(res, step, err)
+			return rp, err
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// checkFinishedOK confirms that a stopped repopull succeeded, is
+// for the wanted commit, and has its timestamps set in order.
+func checkFinishedOK(res *testresult.TestResult, step string, rp *repoPull, wantCommit string) error {
+	var err error
+	switch {
+	case rp.Health != "ok":
+		err = fmt.Errorf("expected health \"ok\", got %q with output %q", rp.Health, rp.Output)
+	case rp.Commit != wantCommit:
+		err = fmt.Errorf("expected commit %s, got %s", wantCommit, rp.Commit)
+	case rp.StartedAt.IsZero():
+		err = fmt.Errorf("started_at was not set")
+	case rp.FinishedAt.IsZero():
+		err = fmt.Errorf("finished_at was not set")
+	case rp.FinishedAt.Before(rp.StartedAt):
+		err = fmt.Errorf("finished_at %v is before started_at %v", rp.FinishedAt, rp.StartedAt)
+	}
+	if err != nil {
+		utils.FailTest(res, step, err)
+	}
+
+	return err
+}
+
+// checkPullFails sends a POST for a repopull that cannot succeed.
+// The API may reject it immediately with an error, or accept it
+// and then stop it with an error. Either way, the error must
+// mention badRef so that the user can tell what went wrong.
+func checkPullFails(res *testresult.TestResult, step string, root string, repoID uint32, branch string, body string, badRef string) error {
+	url := fmt.Sprintf("%s/repos/%d/branches/%s", root, repoID, branch)
+	code, b, err := utils.Send(res, step, "POST", url, body, "operator")
+	if err != nil {
+		utils.FailTest(res, step, err)
+		return err
+	}
+	res.Got = b
+
+	switch {
+	case code >= 400 && code < 500:
+		if !utils.IsError(res) {
+			err = fmt.Errorf("expected error message in response")
+		} else if !strings.Contains(string(b), badRef) {
+			err = fmt.Errorf("error message does not mention %q", badRef)
+		}
+		if err != nil {
+			utils.FailTest(res, step, err)
+		}
+		return err
+
+	case code == 201:
+		var created struct {
+			ID uint32 `json:"id"`
+		}
+		err = json.Unmarshal(b, &created)
+		if err != nil {
+			utils.FailTest(res, step, err)
+			return err
+		}
+		rp, err := waitForPull(res, step, root, created.ID)
+		if err != nil {
+			return err
+		}
+		if rp.Health != "error" {
+			err = fmt.Errorf("expected pull to stop with health \"error\", got %q", rp.Health)
+		} else if !strings.Contains(rp.Output, badRef) {
+			err = fmt.Errorf("pull output %q does not mention %q", rp.Output, badRef)
+		}
+		if err != nil {
+			utils.FailTest(res, step, err)
+		}
+		return err
+
+	default:
+		err = fmt.Errorf("expected HTTP status code 201 or 4xx, got %d", code)
+		utils.FailTest(res, step, err)
+		return err
+	}
+}
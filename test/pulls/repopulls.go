@@ -0,0 +1,160 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package pulls
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// ===== successful pulls
+
+func pullCommitFinishes(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (commit)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	commit := Repos.ByID(2).Head("dev")
+	body := fmt.Sprintf(`{"commit": "%s"}`, commit)
+	id, err := startPull(res, "1", root, 2, "dev", body)
+	if err != nil {
+		return res
+	}
+
+	rp, err := waitForPull(res, "2", root, id)
+	if err != nil {
+		return res
+	}
+	if checkFinishedOK(res, "3", rp, commit) != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func pullTagFinishes(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (tag)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	body := `{"tag": "v2.1.0"}`
+	id, err := startPull(res, "1", root, 2, "dev-2.1", body)
+	if err != nil {
+		return res
+	}
+
+	// once pulled, the tag should have been resolved to its commit
+	rp, err := waitForPull(res, "2", root, id)
+	if err != nil {
+		return res
+	}
+	if checkFinishedOK(res, "3", rp, Repos.ByID(2).Tags["v2.1.0"]) != nil {
+		return res
+	}
+	if rp.Tag != "v2.1.0" {
+		utils.FailTest(res, "4", fmt.Errorf("expected tag %q, got %q", "v2.1.0", rp.Tag))
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== failing pulls
+
+func pullBadCommitFails(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (nonexistent commit)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	badCommit := "0123456789abcdef0123456789abcdef01234567"
+	body := fmt.Sprintf(`{"commit": "%s"}`, badCommit)
+	err := checkPullFails(res, "1", root, 2, "dev", body, badCommit)
+	if err != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func pullCommitOnOtherBranchFails(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (commit not on branch)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	// this commit exists in the repo, but only on dev-2.1
+	commit := Repos.ByID(2).Head("dev-2.1")
+	body := fmt.Sprintf(`{"commit": "%s"}`, commit)
+	err := checkPullFails(res, "1", root, 2, "dev", body, commit)
+	if err != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func pullBadBranchFails(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (branch not in repo)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	// first, register a branch that the git repo does not have
+	url := root + "/repos/2/branches"
+	res.Wanted = utils.Golden(res, "1")
+	err := utils.Post(res, "1", url, `{"branch": "ghost"}`, 201, "operator")
+	if err != nil {
+		return res
+	}
+
+	if !utils.IsMatch(res) {
+		utils.FailMatch(res, "2")
+		return res
+	}
+
+	// now, try to pull it
+	body := fmt.Sprintf(`{"commit": "%s"}`, Repos.ByID(2).Head("master"))
+	err = checkPullFails(res, "3", root, 2, "ghost", body, "ghost")
+	if err != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+func pullBadTagFails(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repos/{id}/branches/{branch}",
+		ID:      "pull (nonexistent tag)",
+		Tags:    []string{"git", "slow"},
+	}
+
+	err := checkPullFails(res, "1", root, 2, "dev-2.1", `{"tag": "v9.9.9"}`, "v9.9.9")
+	if err != nil {
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
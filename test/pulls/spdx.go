@@ -0,0 +1,251 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package pulls
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/internal/spdx"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// SPDXPath is the path, relative to the root URL, from which the
+// SPDX document for a repopull is fetched. It is formatted with
+// the repopull's ID.
+var SPDXPath = "/repopulls/%d/spdx"
+
+// licenseIDRe finds short-form license identifiers in fixture files.
+var licenseIDRe = regexp.MustCompile(`SPDX-License-Identifier:\s*(.*)`)
+
+// pullSPDX pulls the head of a branch in a fixture repository,
+// waits for it to finish, and then fetches and parses its SPDX
+// document. It uses steps 1 through 4.
+func pullSPDX(res *testresult.TestResult, root string, repoID uint32, branch string) (*spdx.Document, error) {
+	commit := Repos.ByID(repoID).Head(branch)
+	body := fmt.Sprintf(`{"commit": "%s"}`, commit)
+	id, err := startPull(res, "1", root, repoID, branch, body)
+	if err != nil {
+		return nil, err
+	}
+
+	rp, err := waitForPull(res, "2", root, id)
+	if err != nil {
+		return nil, err
+	}
+	err = checkFinishedOK(res, "3", rp, commit)
+	if err != nil {
+		return nil, err
+	}
+	if rp.SPDXID == "" {
+		err = fmt.Errorf("finished repopull %d has no spdx_id", id)
+		utils.FailTest(res, "3", err)
+		return nil, err
+	}
+
+	url := root + fmt.Sprintf(SPDXPath, id)
+	err = utils.GetContent(res, "4", url, 200, "viewer")
+	if err != nil {
+		return nil, err
+	}
+	doc, err := spdx.Parse(res.Got)
+	if err != nil {
+		utils.FailTest(res, "4", fmt.Errorf("could not parse SPDX document: %v", err))
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// onlyPackage returns the document's single package, failing the
+// test if there is not exactly one.
+func onlyPackage(res *testresult.TestResult, step string, doc *spdx.Document) (*spdx.Package, error) {
+	if len(doc.Packages) != 1 {
+		err := fmt.Errorf("expected 1 package, got %d", len(doc.Packages))
+		utils.FailTest(res, step, err)
+		return nil, err
+	}
+	return doc.Packages[0], nil
+}
+
+// normalizeFileName strips the leading "./" that SPDX file names
+// usually have, so they can be compared to fixture paths.
+func normalizeFileName(name string) string {
+	return strings.TrimPrefix(name, "./")
+}
+
+// expectedLicenses returns the sorted license identifiers found in
+// a fixture file's SPDX-License-Identifier line, or nil if it has
+// none.
+func expectedLicenses(content string) []string {
+	m := licenseIDRe.FindStringSubmatch(content)
+	if m == nil {
+		return nil
+	}
+
+	ids := []string{}
+	expr := strings.NewReplacer("*/", " ", "(", " ", ")", " ").Replace(m[1])
+	for _, f := range strings.Fields(expr) {
+		if f != "OR" && f != "AND" && f != "WITH" {
+			ids = append(ids, f)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// ===== SPDX document structure
+
+func spdxStructure(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repopulls/{id}/spdx",
+		ID:      "structure",
+		Tags:    []string{"git", "slow"},
+	}
+
+	doc, err := pullSPDX(res, root, 2, "dev-2.1")
+	if err != nil {
+		return res
+	}
+
+	if errs := spdx.Validate(doc); errs != nil {
+		utils.FailTest(res, "5", fmt.Errorf("invalid SPDX document: %v", errs))
+		return res
+	}
+
+	// the package must have exactly the files in the fixture tree
+	pkg, err := onlyPackage(res, "6", doc)
+	if err != nil {
+		return res
+	}
+	got := []string{}
+	for _, f := range doc.PackageFiles(pkg) {
+		got = append(got, normalizeFileName(f.Name))
+	}
+	want := []string{}
+	for path := range Repos.ByID(2).Files[Repos.ByID(2).Head("dev-2.1")] {
+		want = append(want, path)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		utils.FailTest(res, "7", fmt.Errorf("expected package files %v, got %v", want, got))
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== SPDX package verification code
+
+func spdxVerificationCode(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repopulls/{id}/spdx",
+		ID:      "verification code",
+		Tags:    []string{"git", "slow"},
+	}
+
+	doc, err := pullSPDX(res, root, 1, "testing")
+	if err != nil {
+		return res
+	}
+
+	pkg, err := onlyPackage(res, "5", doc)
+	if err != nil {
+		return res
+	}
+
+	// each file's checksum must match the fixture content, and the
+	// verification code must match the fixture content as a whole
+	repo := Repos.ByID(1)
+	tree := repo.Files[repo.Head("testing")]
+	sha1s := []string{}
+	for path, content := range tree {
+		sum := fmt.Sprintf("%x", sha1.Sum([]byte(content)))
+		sha1s = append(sha1s, sum)
+		f := findFile(doc, pkg, path)
+		if f == nil {
+			utils.FailTest(res, "6", fmt.Errorf("file %s missing from package", path))
+			return res
+		}
+		if f.Checksums["SHA1"] != sum {
+			utils.FailTest(res, "6", fmt.Errorf("file %s has SHA1 %s, expected %s", path, f.Checksums["SHA1"], sum))
+			return res
+		}
+	}
+	if want := spdx.VerificationCode(sha1s); pkg.VerificationCode != want {
+		utils.FailTest(res, "7", fmt.Errorf("expected package verification code %s, got %s", want, pkg.VerificationCode))
+		return res
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// ===== SPDX file license fields
+
+func spdxFileLicenses(root string) *testresult.TestResult {
+	res := &testresult.TestResult{
+		Suite:   "pulls",
+		Element: "repopulls/{id}/spdx",
+		ID:      "file licenses",
+		Tags:    []string{"git", "slow"},
+	}
+
+	doc, err := pullSPDX(res, root, 4, "master")
+	if err != nil {
+		return res
+	}
+
+	pkg, err := onlyPackage(res, "5", doc)
+	if err != nil {
+		return res
+	}
+
+	// files with an SPDX-License-Identifier must report exactly
+	// those licenses; files without one must not claim any
+	repo := Repos.ByID(4)
+	for path, content := range repo.Files[repo.Head("master")] {
+		f := findFile(doc, pkg, path)
+		if f == nil {
+			utils.FailTest(res, "6", fmt.Errorf("file %s missing from package", path))
+			return res
+		}
+
+		got := append([]string{}, f.LicenseInfoInFile...)
+		sort.Strings(got)
+		want := expectedLicenses(content)
+		if want == nil {
+			if len(got) != 1 || (got[0] != "NONE" && got[0] != "NOASSERTION") {
+				utils.FailTest(res, "7", fmt.Errorf("file %s has no license identifier but reports %v", path, got))
+				return res
+			}
+			continue
+		}
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			utils.FailTest(res, "7", fmt.Errorf("file %s: expected licenses %v, got %v", path, want, got))
+			return res
+		}
+	}
+
+	utils.Pass(res)
+	return res
+}
+
+// findFile returns the file in the package with the given fixture
+// path, or nil.
+func findFile(doc *spdx.Document, pkg *spdx.Package, path string) *spdx.File {
+	for _, f := range doc.PackageFiles(pkg) {
+		if normalizeFileName(f.Name) == path {
+			return f
+		}
+	}
+	return nil
+}
@@ -0,0 +1,284 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package scale
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// ===== GET /projects
+
+func projectsList(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "projects",
+			ID:      fmt.Sprintf("GET (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{Projects: n})
+		if err != nil {
+			return res
+		}
+
+		var list struct {
+			Projects []item `json:"projects"`
+		}
+		err = getList(res, "1", root+"/projects", &list, func() int { return len(list.Projects) })
+		if err != nil {
+			return res
+		}
+
+		want := idSet(3)
+		names := map[uint32]string{}
+		for _, p := range g.Projects {
+			want[p.ID] = true
+			names[p.ID] = p.Name
+		}
+		got := []uint32{}
+		for _, p := range list.Projects {
+			got = append(got, p.ID)
+			if name, ok := names[p.ID]; ok && p.Name != name {
+				utils.FailTest(res, "2", fmt.Errorf("project %d has name %q, expected %q", p.ID, p.Name, name))
+				return res
+			}
+		}
+		if checkIDs(res, "2", got, want) != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET /repos
+
+func reposList(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "repos",
+			ID:      fmt.Sprintf("GET (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{
+			Projects:              1,
+			SubprojectsPerProject: 2,
+			ReposPerSubproject:    (n + 1) / 2,
+		})
+		if err != nil {
+			return res
+		}
+
+		var list struct {
+			Repos []item `json:"repos"`
+		}
+		err = getList(res, "1", root+"/repos", &list, func() int { return len(list.Repos) })
+		if err != nil {
+			return res
+		}
+
+		want := idSet(4)
+		for _, r := range g.Repos {
+			want[r.ID] = true
+		}
+		got := []uint32{}
+		for _, r := range list.Repos {
+			got = append(got, r.ID)
+		}
+		if checkIDs(res, "2", got, want) != nil {
+			return res
+		}
+
+		// and the nested list has only the first subproject's repos
+		sp := g.Subprojects[0]
+		var nested struct {
+			Repos []item `json:"repos"`
+		}
+		err = getList(res, "3", fmt.Sprintf("%s/subprojects/%d/repos", root, sp.ID), &nested, func() int { return len(nested.Repos) })
+		if err != nil {
+			return res
+		}
+		want = map[uint32]bool{}
+		for _, r := range g.Repos {
+			if r.SubprojectID == sp.ID {
+				want[r.ID] = true
+			}
+		}
+		got = []uint32{}
+		for _, r := range nested.Repos {
+			got = append(got, r.ID)
+		}
+		if checkIDs(res, "4", got, want) != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET /repos/id/branches
+
+func branchesList(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "repos/{id}/branches",
+			ID:      fmt.Sprintf("GET (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{
+			Projects:              1,
+			SubprojectsPerProject: 1,
+			ReposPerSubproject:    1,
+			BranchesPerRepo:       n,
+		})
+		if err != nil {
+			return res
+		}
+		repoID := g.Repos[0].ID
+
+		var list struct {
+			Branches []string `json:"branches"`
+		}
+		url := fmt.Sprintf("%s/repos/%d/branches", root, repoID)
+		err = getList(res, "1", url, &list, func() int { return len(list.Branches) })
+		if err != nil {
+			return res
+		}
+
+		// branches have no IDs, so just check that they match and
+		// that a second call lists them in the same order
+		want := append([]string{}, g.Branches[repoID]...)
+		got := append([]string{}, list.Branches...)
+		sort.Strings(want)
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			utils.FailTest(res, "2", fmt.Errorf("expected %d branches, got %d, or with different names", len(want), len(got)))
+			return res
+		}
+
+		first := list.Branches
+		err = getList(res, "3", url, &list, func() int { return len(list.Branches) })
+		if err != nil {
+			return res
+		}
+		if fmt.Sprint(first) != fmt.Sprint(list.Branches) {
+			utils.FailTest(res, "4", fmt.Errorf("branches are listed in a different order each time"))
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET /repos/id/branches/branch
+
+func pullsList(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "repos/{id}/branches/{branch}",
+			ID:      fmt.Sprintf("GET (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{
+			Projects:              1,
+			SubprojectsPerProject: 1,
+			ReposPerSubproject:    1,
+			BranchesPerRepo:       1,
+			PullsPerBranch:        n,
+		})
+		if err != nil {
+			return res
+		}
+		repoID := g.Repos[0].ID
+		branch := g.Branches[repoID][0]
+
+		var list struct {
+			Pulls []item `json:"pulls"`
+		}
+		u := fmt.Sprintf("%s/repos/%d/branches/%s", root, repoID, url.PathEscape(branch))
+		err = getList(res, "1", u, &list, func() int { return len(list.Pulls) })
+		if err != nil {
+			return res
+		}
+
+		want := map[uint32]bool{}
+		for _, p := range g.Pulls {
+			want[p.ID] = true
+		}
+		got := []uint32{}
+		for _, p := range list.Pulls {
+			got = append(got, p.ID)
+		}
+		if checkIDs(res, "2", got, want) != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET /repopulls/id/jobs
+
+func jobsList(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "repopulls/{id}/jobs",
+			ID:      fmt.Sprintf("GET (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{
+			Projects:              1,
+			SubprojectsPerProject: 1,
+			ReposPerSubproject:    1,
+			BranchesPerRepo:       1,
+			PullsPerBranch:        1,
+			JobsPerPull:           n,
+		})
+		if err != nil {
+			return res
+		}
+
+		var list struct {
+			Jobs []item `json:"jobs"`
+		}
+		u := fmt.Sprintf("%s/repopulls/%d/jobs", root, g.Pulls[0].ID)
+		err = getList(res, "1", u, &list, func() int { return len(list.Jobs) })
+		if err != nil {
+			return res
+		}
+
+		want := map[uint32]bool{}
+		for _, j := range g.Jobs {
+			want[j.ID] = true
+		}
+		got := []uint32{}
+		for _, j := range list.Jobs {
+			got = append(got, j.ID)
+		}
+		if checkIDs(res, "2", got, want) != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package scale
+
+import (
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// pagingStyle is a common way of asking for one page of a list.
+// The API may support any or none of them; one that it ignores
+// returns the whole list, and is skipped.
+type pagingStyle struct {
+	query string
+
+	// first and count say which items of the full list the query
+	// should return.
+	first int
+	count int
+}
+
+var pagingStyles = []pagingStyle{
+	{query: "limit=10&offset=5", first: 5, count: 10},
+	{query: "page=2&per_page=10", first: 10, count: 10},
+	{query: "page=2&page_size=10", first: 10, count: 10},
+}
+
+// ===== GET /projects?paging
+
+func projectsPaging(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "projects",
+			ID:      fmt.Sprintf("GET paging (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		_, err := generate(res, root, fixtures.ScaleConfig{Projects: n})
+		if err != nil {
+			return res
+		}
+
+		var full struct {
+			Projects []item `json:"projects"`
+		}
+		err = getList(res, "1", root+"/projects", &full, func() int { return len(full.Projects) })
+		if err != nil {
+			return res
+		}
+
+		for i, ps := range pagingStyles {
+			step := fmt.Sprintf("%d", i+2)
+			var page struct {
+				Projects []item `json:"projects"`
+			}
+			err = getList(res, step, root+"/projects?"+ps.query, &page, func() int { return len(page.Projects) })
+			if err != nil {
+				return res
+			}
+			if len(page.Projects) == len(full.Projects) {
+				// not supported
+				continue
+			}
+
+			want := []item{}
+			for j := ps.first; j < ps.first+ps.count && j < len(full.Projects); j++ {
+				want = append(want, full.Projects[j])
+			}
+			if fmt.Sprint(page.Projects) != fmt.Sprint(want) {
+				utils.FailTest(res, step, fmt.Errorf("?%s returned %d projects, but not items %d to %d of the full list", ps.query, len(page.Projects), ps.first, ps.first+ps.count-1))
+				return res
+			}
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// ===== GET /repos?subproject_id=
+
+func reposFilter(n int) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "scale",
+			Element: "repos",
+			ID:      fmt.Sprintf("GET filter (%d generated)", n),
+			Tags:    []string{"slow"},
+		}
+
+		g, err := generate(res, root, fixtures.ScaleConfig{
+			Projects:              1,
+			SubprojectsPerProject: 2,
+			ReposPerSubproject:    (n + 1) / 2,
+		})
+		if err != nil {
+			return res
+		}
+		sp := g.Subprojects[1]
+
+		var list struct {
+			Repos []item `json:"repos"`
+		}
+		err = getList(res, "1", fmt.Sprintf("%s/repos?subproject_id=%d", root, sp.ID), &list, func() int { return len(list.Repos) })
+		if err != nil {
+			return res
+		}
+		if len(list.Repos) == 4+len(g.Repos) {
+			// not supported, so the whole list was returned
+			utils.Pass(res)
+			return res
+		}
+
+		want := map[uint32]bool{}
+		for _, r := range g.Repos {
+			if r.SubprojectID == sp.ID {
+				want[r.ID] = true
+			}
+		}
+		got := []uint32{}
+		for _, r := range list.Repos {
+			got = append(got, r.ID)
+		}
+		if checkIDs(res, "2", got, want) != nil {
+			return res
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package scale generates large numbers of objects and checks that
+// the list endpoints still return all of them, in order, within
+// size and latency limits, and that any paging or filtering query
+// parameters that the API supports behave consistently.
+package scale
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/swinslow/peridot-api-testing/fixtures"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Sizes are the numbers of objects to generate for each list. Each
+// size gets its own set of tests.
+var Sizes = []int{}
+
+// Seed is the seed for the generated objects.
+var Seed int64 = 1
+
+// MaxLatency is the longest that a list call may take.
+var MaxLatency = 2 * time.Second
+
+// MaxItemBytes is the largest average size, in bytes, of one item
+// in a list response.
+var MaxItemBytes = 1024
+
+// GetTests returns all of the scale test suites, for each size.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	for _, n := range Sizes {
+		allTests = append(allTests,
+			projectsList(n),
+			reposList(n),
+			branchesList(n),
+			pullsList(n),
+			jobsList(n),
+			projectsPaging(n),
+			reposFilter(n),
+		)
+	}
+
+	return allTests
+}
+
+// generate creates objects for a test, failing step "0" if it
+// cannot.
+func generate(res *testresult.TestResult, root string, cfg fixtures.ScaleConfig) (*fixtures.Generated, error) {
+	cfg.Seed = Seed
+	g, err := fixtures.Generate(root, cfg)
+	if err != nil {
+		utils.FailTest(res, "0", err)
+		return nil, err
+	}
+	return g, nil
+}
+
+// getList fetches a list as viewer and unmarshals it into out,
+// checking the call's latency and the average size of its items,
+// as counted by count.
+func getList(res *testresult.TestResult, step string, url string, out interface{}, count func() int) error {
+	start := time.Now()
+	err := utils.GetContent(res, step, url, 200, "viewer")
+	if err != nil {
+		return err
+	}
+	elapsed := time.Since(start)
+
+	err = json.Unmarshal(res.Got, out)
+	if err != nil {
+		utils.FailTest(res, step, fmt.Errorf("could not parse list: %v", err))
+		return err
+	}
+
+	if elapsed > MaxLatency {
+		err = fmt.Errorf("list took %v, more than the limit of %v", elapsed, MaxLatency)
+		utils.FailTest(res, step, err)
+		return err
+	}
+	if n := count(); n > 0 && len(res.Got)/n > MaxItemBytes {
+		err = fmt.Errorf("list of %d items is %d bytes, more than %d bytes per item", n, len(res.Got), MaxItemBytes)
+		utils.FailTest(res, step, err)
+		return err
+	}
+
+	return nil
+}
+
+// item is the part of a listed object that the checks need.
+type item struct {
+	ID   uint32 `json:"id"`
+	Name string `json:"name"`
+}
+
+// checkIDs checks that got holds exactly the wanted IDs, with no
+// duplicates, in ascending order.
+func checkIDs(res *testresult.TestResult, step string, got []uint32, want map[uint32]bool) error {
+	seen := map[uint32]bool{}
+	for i, id := range got {
+		if seen[id] {
+			return fail(res, step, fmt.Errorf("ID %d is listed more than once", id))
+		}
+		seen[id] = true
+		if !want[id] {
+			return fail(res, step, fmt.Errorf("unexpected ID %d in list", id))
+		}
+		if i > 0 && got[i-1] > id {
+			return fail(res, step, fmt.Errorf("list is not in ascending ID order: %d is listed before %d", got[i-1], id))
+		}
+	}
+	if len(seen) != len(want) {
+		missing := []uint32{}
+		for id := range want {
+			if !seen[id] {
+				missing = append(missing, id)
+			}
+		}
+		return fail(res, step, fmt.Errorf("expected %d items, got %d; %d missing, e.g. %d", len(want), len(got), len(missing), missing[0]))
+	}
+	return nil
+}
+
+// fail records a failure at step and returns err.
+func fail(res *testresult.TestResult, step string, err error) error {
+	utils.FailTest(res, step, err)
+	return err
+}
+
+// idSet returns the set of IDs from 1 to n, for the objects that
+// the fixture creates.
+func idSet(n uint32) map[uint32]bool {
+	ids := map[uint32]bool{}
+	for id := uint32(1); id <= n; id++ {
+		ids[id] = true
+	}
+	return ids
+}
@@ -0,0 +1,147 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/swinslow/peridot-api-testing/client"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// state maps a key for each fixture object, such as "projects/2",
+// to a description of its contents.
+type state map[string]string
+
+// fixtureBranches are the branches whose repopulls are included in
+// the state.
+var fixtureBranches = []struct {
+	repoID uint32
+	branch string
+}{
+	{1, "master"},
+	{1, "testing"},
+	{2, "master"},
+	{2, "dev"},
+	{2, "dev-2.1"},
+	{4, "master"},
+}
+
+// getState fetches, as admin, everything that the fixture creates.
+// Only the fields that no probe should be able to change are
+// included for repopulls and jobs, since their status can change
+// on its own.
+func getState(root string) (state, error) {
+	c := client.New(root, utils.Identity("admin"))
+	s := state{}
+
+	add := func(key string, v interface{}) error {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		s[key] = string(b)
+		return nil
+	}
+
+	users, err := c.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		add(fmt.Sprintf("users/%d", u.ID), u)
+	}
+
+	projects, err := c.ListProjects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		add(fmt.Sprintf("projects/%d", p.ID), p)
+	}
+
+	subprojects, err := c.ListSubprojects()
+	if err != nil {
+		return nil, err
+	}
+	for _, sp := range subprojects {
+		add(fmt.Sprintf("subprojects/%d", sp.ID), sp)
+	}
+
+	repos, err := c.ListRepos()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range repos {
+		add(fmt.Sprintf("repos/%d", r.ID), r)
+		branches, err := c.ListRepoBranches(r.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range branches {
+			s[fmt.Sprintf("repos/%d/branches/%s", r.ID, b)] = ""
+		}
+	}
+
+	agents, err := c.ListAgents()
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range agents {
+		add(fmt.Sprintf("agents/%d", a.ID), a)
+	}
+
+	for _, fb := range fixtureBranches {
+		pulls, err := c.ListRepoPulls(fb.repoID, fb.branch)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range pulls {
+			s[fmt.Sprintf("repopulls/%d", p.ID)] = fmt.Sprintf("%d %s %s %s", p.RepoID, p.Branch, p.Commit, p.Tag)
+			jobs, err := c.ListJobs(p.ID)
+			if err != nil {
+				return nil, err
+			}
+			for _, j := range jobs {
+				add(fmt.Sprintf("jobs/%d", j.ID), []interface{}{j.RepoPullID, j.AgentID, j.PriorJobIDs, j.Config})
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// checkIntegrity confirms that every object in before is still
+// present and unchanged, apart from those whose keys are in except.
+// New objects are allowed.
+func checkIntegrity(root string, before state, except []string) error {
+	after, err := getState(root)
+	if err != nil {
+		return fmt.Errorf("could not get state for integrity check: %v", err)
+	}
+
+	skip := map[string]bool{}
+	for _, k := range except {
+		skip[k] = true
+	}
+
+	changed := []string{}
+	for k, v := range before {
+		if skip[k] {
+			continue
+		}
+		av, ok := after[k]
+		if !ok {
+			changed = append(changed, k+" (removed)")
+		} else if av != v {
+			changed = append(changed, fmt.Sprintf("%s (was %s, now %s)", k, v, av))
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		return fmt.Errorf("fixture objects changed: %v", changed)
+	}
+	return nil
+}
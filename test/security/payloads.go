@@ -0,0 +1,93 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package security
+
+import (
+	"net/url"
+	"strings"
+)
+
+// payload is one hostile value to send in place of an ID, branch
+// name, string field or query parameter.
+type payload struct {
+	// value is the payload as the API should see it after any
+	// decoding.
+	value string
+
+	// raw, if true, means value is already in the form it should
+	// take in a URL, such as a pre-encoded string, and must not be
+	// escaped again.
+	raw bool
+}
+
+// inPath returns the payload as it should appear in a path segment.
+func (p payload) inPath() string {
+	if p.raw {
+		return p.value
+	}
+	return url.PathEscape(p.value)
+}
+
+// inQuery returns the payload as it should appear as a query
+// parameter value.
+func (p payload) inQuery() string {
+	if p.raw {
+		return p.value
+	}
+	return url.QueryEscape(p.value)
+}
+
+// category is a named group of payloads.
+type category struct {
+	name     string
+	payloads []payload
+}
+
+var categories = []category{
+	{
+		name: "sqli",
+		payloads: []payload{
+			{value: `2 OR 1=1`},
+			{value: `2' OR '1'='1`},
+			{value: `2; DROP TABLE projects; --`},
+			{value: `2) UNION SELECT NULL, NULL, NULL --`},
+			{value: `dev'--`},
+			{value: `'; UPDATE users SET access = 'admin' WHERE '1'='1`},
+		},
+	},
+	{
+		name: "traversal",
+		payloads: []payload{
+			{value: `../2`, raw: true},
+			{value: `../../admin/db`, raw: true},
+			{value: `../../../../etc/passwd`},
+			{value: `..\..\..\windows\win.ini`},
+		},
+	},
+	{
+		name: "encoding",
+		payloads: []payload{
+			{value: `%2e%2e%2f2`, raw: true},
+			{value: `%252e%252e%252f2`, raw: true},
+			{value: `2%00`, raw: true},
+			{value: `%c0%ae%c0%ae%2f2`, raw: true},
+			{value: `%27%20OR%20%271%27%3D%271`, raw: true},
+			{value: "dev\u202e1.2-ved"},
+			{value: "\ufeff2"},
+		},
+	},
+	{
+		name: "oversized",
+		payloads: []payload{
+			{value: `99999999999999999999999999999999`},
+			{value: `4294967298`},
+			{value: `-1`},
+			{value: strings.Repeat("A", 8*1024)},
+			{value: strings.Repeat("\u00e9", 4*1024)},
+		},
+	},
+}
+
+// bigString is sent in JSON string fields in addition to the
+// payloads, since bodies have no length limit from the URL.
+var bigString = strings.Repeat("x", 1024*1024)
@@ -0,0 +1,218 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+// Package security sends hostile values in paths, bodies and query
+// strings, as every role, and checks that the API neither fails
+// with a server error, nor leaks internal errors, nor changes any
+// object it was not asked to change.
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// roles are the identities that each probe is sent as.
+var roles = []string{"none", "admin", "operator", "commenter", "viewer", "disabled"}
+
+// target is somewhere that a payload can be sent.
+type target struct {
+	element string
+	method  string
+
+	// path returns the path, and any query string, for a payload.
+	path func(p payload) string
+
+	// body returns the request body for a value, or "" for none.
+	body func(v string) string
+
+	// except lists the fixture objects that the call may
+	// legitimately change.
+	except []string
+}
+
+// jsonString returns v encoded as a JSON string.
+func jsonString(v string) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func idPath(prefix string) func(p payload) string {
+	return func(p payload) string {
+		return prefix + p.inPath()
+	}
+}
+
+func fixedPath(path string) func(p payload) string {
+	return func(p payload) string {
+		return path
+	}
+}
+
+var targets = []target{
+	// path segments
+	{element: "projects/{id}", method: "GET", path: idPath("/projects/")},
+	{element: "subprojects/{id}", method: "GET", path: idPath("/subprojects/")},
+	{element: "repos/{id}", method: "GET", path: idPath("/repos/")},
+	{element: "repopulls/{id}", method: "GET", path: idPath("/repopulls/")},
+	{element: "jobs/{id}", method: "GET", path: idPath("/jobs/")},
+	{element: "agents/{id}", method: "GET", path: idPath("/agents/")},
+	{element: "users/{id}", method: "GET", path: idPath("/users/")},
+	{element: "projects/{id}", method: "DELETE", path: idPath("/projects/")},
+	{element: "repos/{id}/branches/{branch}", method: "GET", path: idPath("/repos/2/branches/")},
+	{
+		element: "repos/{id}/branches/{branch}",
+		method:  "POST",
+		path:    idPath("/repos/2/branches/"),
+		body:    func(v string) string { return `{"commit": "b1da4a64aaf7587ffa78803922337864e74c9f54"}` },
+	},
+
+	// JSON string fields
+	{
+		element: "projects",
+		method:  "POST",
+		path:    fixedPath("/projects"),
+		body: func(v string) string {
+			return fmt.Sprintf(`{"name": %s, "fullname": %s}`, jsonString(v), jsonString(v))
+		},
+	},
+	{
+		element: "projects/{id}",
+		method:  "PUT",
+		path:    fixedPath("/projects/2"),
+		body: func(v string) string {
+			return fmt.Sprintf(`{"name": %s}`, jsonString(v))
+		},
+		except: []string{"projects/2"},
+	},
+	{
+		element: "repos/{id}/branches",
+		method:  "POST",
+		path:    fixedPath("/repos/2/branches"),
+		body: func(v string) string {
+			return fmt.Sprintf(`{"branch": %s}`, jsonString(v))
+		},
+	},
+	{
+		element: "users",
+		method:  "POST",
+		path:    fixedPath("/users"),
+		body: func(v string) string {
+			return fmt.Sprintf(`{"name": %s, "github": %s, "access": "viewer"}`, jsonString(v), jsonString(v))
+		},
+	},
+	{
+		element: "agents",
+		method:  "POST",
+		path:    fixedPath("/agents"),
+		body: func(v string) string {
+			return fmt.Sprintf(`{"name": %s, "is_active": false, "address": %s, "port": 2000, "is_codereader": false, "is_spdxreader": false, "is_codewriter": false, "is_spdxwriter": false}`, jsonString(v), jsonString(v))
+		},
+	},
+
+	// query strings
+	{
+		element: "projects?name=",
+		method:  "GET",
+		path:    func(p payload) string { return "/projects?name=" + p.inQuery() },
+	},
+	{
+		element: "repos?subproject_id=",
+		method:  "GET",
+		path:    func(p payload) string { return "/repos?subproject_id=" + p.inQuery() },
+	},
+}
+
+// GetTests returns all of the security probe test suites.
+func GetTests() []testresult.TestFunc {
+	allTests := []testresult.TestFunc{}
+
+	for _, t := range targets {
+		for _, c := range categories {
+			allTests = append(allTests, probeTest(t, c))
+		}
+	}
+
+	return allTests
+}
+
+// probeTest returns a test that sends each of the category's
+// payloads to the target as each role, checking the response and
+// the fixture's integrity after each one.
+func probeTest(t target, c category) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "security",
+			Element: t.element,
+			ID:      fmt.Sprintf("%s (%s)", t.method, c.name),
+			Tags:    []string{"destructive"},
+		}
+
+		before, err := getState(root)
+		if err != nil {
+			utils.FailTest(res, "0", fmt.Errorf("could not get initial state: %v", err))
+			return res
+		}
+
+		values := []payload{}
+		values = append(values, c.payloads...)
+		if t.body != nil && c.name == "oversized" {
+			values = append(values, payload{value: bigString})
+		}
+
+		step := 0
+		for _, p := range values {
+			for _, role := range roles {
+				step++
+				err = probe(res, fmt.Sprintf("%d", step), root, t, p, role)
+				if err == nil {
+					err = checkIntegrity(root, before, t.except)
+				}
+				if err != nil {
+					utils.FailTest(res, fmt.Sprintf("%d", step), fmt.Errorf("as %s, payload %s: %v", role, describe(p), err))
+					return res
+				}
+			}
+		}
+
+		utils.Pass(res)
+		return res
+	}
+}
+
+// probe sends one payload to the target as the given role, as a
+// step of res, and checks the response.
+func probe(res *testresult.TestResult, step string, root string, t target, p payload, role string) error {
+	body := ""
+	if t.body != nil {
+		body = t.body(p.value)
+	}
+
+	code, b, err := utils.Send(res, step, t.method, root+t.path(p), body, role)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	if code >= 500 {
+		return fmt.Errorf("got server error %d: %s", code, truncate(string(b)))
+	}
+
+	if m := utils.FindLeak(b); m != "" {
+		return fmt.Errorf("response contains %q, which looks like a leak: %s", m, truncate(string(b)))
+	}
+
+	return nil
+}
+
+// describe returns a short printable form of a payload.
+func describe(p payload) string {
+	return fmt.Sprintf("%q", truncate(p.value))
+}
+
+func truncate(s string) string {
+	if len(s) > 80 {
+		return s[:80] + fmt.Sprintf("... (%d bytes)", len(s))
+	}
+	return s
+}
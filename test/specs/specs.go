@@ -0,0 +1,38 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package specs
+
+import (
+	"github.com/swinslow/peridot-api-testing/internal/spec"
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+	"github.com/swinslow/peridot-api-testing/test/utils"
+)
+
+// Dir is the directory holding the YAML spec files for this suite.
+// See package spec for their format.
+var Dir = "test/specs"
+
+// GetTests returns a test for each case in the spec files.
+func GetTests() []testresult.TestFunc {
+	allTests, err := spec.Load(Dir)
+	if err != nil {
+		return []testresult.TestFunc{specsError(err)}
+	}
+	return allTests
+}
+
+// specsError returns a test that always fails because the spec
+// files could not be loaded, so that the problem is reported
+// alongside the other results.
+func specsError(err error) testresult.TestFunc {
+	return func(root string) *testresult.TestResult {
+		res := &testresult.TestResult{
+			Suite:   "specs",
+			Element: "specs",
+			ID:      "load",
+		}
+
+		utils.FailTest(res, "0", err)
+		return res
+	}
+}
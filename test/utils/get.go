@@ -25,8 +25,13 @@ func GetContent(res *testresult.TestResult, step string, url string, code int, g
 	}
 	AddAuthHeader(res, step, req, ghUsername)
 	resp, err := client.Do(req)
+	if err != nil {
+		Record(res, step, "GET", url, ghUsername, "", 0, nil, err)
+		FailTest(res, step, err)
+		return err
+	}
 
-	return helperGetContent(res, resp, step, code)
+	return helperGetContent(res, resp, step, url, code, ghUsername)
 }
 
 // GetContentNoFollow makes an HTTP GET call to the indicated
@@ -46,17 +51,18 @@ func GetContentNoFollow(res *testresult.TestResult, step string, url string, cod
 	AddAuthHeader(res, step, req, ghUsername)
 	resp, err := client.Do(req)
 	if err != nil {
+		Record(res, step, "GET", url, ghUsername, "", 0, nil, err)
 		FailTest(res, step, err)
 		return err
 	}
 
-	return helperGetContent(res, resp, step, code)
+	return helperGetContent(res, resp, step, url, code, ghUsername)
 }
 
 // helperGetContent does the rest of the GetContent or
 // GetContentNoFollow activities, after the decision is
 // made on whether to follow any redirects.
-func helperGetContent(res *testresult.TestResult, resp *http.Response, step string, code int) error {
+func helperGetContent(res *testresult.TestResult, resp *http.Response, step string, url string, code int, ghUsername string) error {
 	// parse content body
 	b, err := ioutil.ReadAll(resp.Body)
 	resp.Body.Close()
@@ -67,6 +73,7 @@ func helperGetContent(res *testresult.TestResult, resp *http.Response, step stri
 
 	// record in testresult
 	res.Got = b
+	Record(res, step, "GET", url, ghUsername, "", resp.StatusCode, b, nil)
 
 	// check expected status code
 	if resp.StatusCode != code {
@@ -0,0 +1,193 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// GoldenDir is the directory holding the golden files, which hold
+// the expected response bodies for the tests.
+var GoldenDir = "testdata/golden"
+
+// UpdateGolden, if true, means that a response that does not match
+// its golden file is recorded as an update to that file, and the
+// rest of the test still runs. CheckGolden then fails the test, and
+// the updates are written out by WriteGoldenUpdates.
+var UpdateGolden = false
+
+// GoldenUpdate is a golden file whose contents differ from the
+// response that it was compared to.
+type GoldenUpdate struct {
+	// File is the path to the golden file.
+	File string
+
+	// Old is its current contents, or nil if it does not exist.
+	Old []byte
+
+	// New is the response, formatted for the golden file.
+	New []byte
+}
+
+// goldenRef records which golden file a test's Wanted string was
+// read from.
+type goldenRef struct {
+	file   string
+	wanted string
+}
+
+// goldenRefs holds each test's latest golden file until its
+// response is compared, and goldenChanged the golden files that a
+// test's responses differed from, until CheckGolden is called.
+var (
+	goldenMu      sync.Mutex
+	goldenRefs    = map[*testresult.TestResult]goldenRef{}
+	goldenChanged = map[*testresult.TestResult][]string{}
+	goldenUpdates = map[string]*GoldenUpdate{}
+)
+
+// GoldenPath returns the path to the golden file for a step of a
+// test, keyed by its suite, element and ID.
+func GoldenPath(res *testresult.TestResult, step string) string {
+	return filepath.Join(GoldenDir, slug(res.Suite), slug(res.Element), slug(res.ID), step+".json")
+}
+
+// Golden returns the contents of the golden file for a step of a
+// test, for use as its Wanted string. The step is the one whose
+// response is compared against the file. If the file cannot be
+// read, the test is failed, unless UpdateGolden is set.
+func Golden(res *testresult.TestResult, step string) string {
+	file := GoldenPath(res, step)
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		b = nil
+		if !UpdateGolden {
+			FailTest(res, step, fmt.Errorf("could not read golden file: %v", err))
+		}
+	}
+
+	goldenMu.Lock()
+	goldenRefs[res] = goldenRef{file: file, wanted: string(b)}
+	goldenMu.Unlock()
+
+	return string(b)
+}
+
+// recordGoldenMismatch records the response as an update to the
+// golden file that res.Wanted was read from, if there is one, and
+// returns whether it did.
+func recordGoldenMismatch(res *testresult.TestResult) bool {
+	goldenMu.Lock()
+	defer goldenMu.Unlock()
+
+	ref, ok := goldenRefs[res]
+	if !ok || ref.wanted != res.Wanted {
+		return false
+	}
+
+	u := &GoldenUpdate{File: ref.file, New: formatGolden(res.Got)}
+	if _, err := os.Stat(ref.file); err == nil {
+		u.Old = []byte(ref.wanted)
+	}
+	goldenUpdates[ref.file] = u
+	goldenChanged[res] = append(goldenChanged[res], ref.file)
+	return true
+}
+
+// forgetGolden forgets which golden file res.Wanted was read from,
+// once it has been compared.
+func forgetGolden(res *testresult.TestResult) {
+	goldenMu.Lock()
+	delete(goldenRefs, res)
+	goldenMu.Unlock()
+}
+
+// CheckGolden is called once a test has run. It fails the test if
+// any of its responses differed from their golden files, which
+// IsMatch let it run past so that they could be recorded as
+// updates, and forgets the golden files that the test used.
+func CheckGolden(res *testresult.TestResult) {
+	goldenMu.Lock()
+	files := goldenChanged[res]
+	delete(goldenChanged, res)
+	delete(goldenRefs, res)
+	goldenMu.Unlock()
+
+	if len(files) == 0 || !res.Success {
+		return
+	}
+	err := fmt.Errorf("response differs from golden file %s; recorded as an update", files[0])
+	if len(files) > 1 {
+		err = fmt.Errorf("responses differ from %d golden files, starting with %s; recorded as updates", len(files), files[0])
+	}
+	FailTest(res, strings.TrimSuffix(filepath.Base(files[0]), ".json"), err)
+}
+
+// GoldenUpdates returns the recorded updates, sorted by file.
+func GoldenUpdates() []*GoldenUpdate {
+	goldenMu.Lock()
+	defer goldenMu.Unlock()
+
+	us := []*GoldenUpdate{}
+	for _, u := range goldenUpdates {
+		us = append(us, u)
+	}
+	sort.Slice(us, func(i, j int) bool { return us[i].File < us[j].File })
+	return us
+}
+
+// WriteGoldenUpdates writes the new contents of each golden file.
+func WriteGoldenUpdates(us []*GoldenUpdate) error {
+	for _, u := range us {
+		err := os.MkdirAll(filepath.Dir(u.File), 0755)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(u.File, u.New, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatGolden indents a JSON response for a golden file. Anything
+// that isn't JSON is kept as it is.
+func formatGolden(got []byte) []byte {
+	var buf bytes.Buffer
+	if json.Indent(&buf, got, "", "  ") != nil {
+		return got
+	}
+	buf.WriteString("\n")
+	return buf.Bytes()
+}
+
+// slug turns a suite, element or ID into a directory name, keeping
+// letters, digits, '.' and '_', lowercased, and joining everything
+// else into single dashes.
+func slug(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '_' {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+	return b.String()
+}
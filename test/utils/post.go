@@ -28,6 +28,7 @@ func Post(res *testresult.TestResult, step string, url string, bodystr string, c
 	AddAuthHeader(res, step, req, ghUsername)
 	resp, err := client.Do(req)
 	if err != nil {
+		Record(res, step, "POST", url, ghUsername, bodystr, 0, nil, err)
 		FailTest(res, step, err)
 		return err
 	}
@@ -42,6 +43,7 @@ func Post(res *testresult.TestResult, step string, url string, bodystr string, c
 
 	// record in testresult
 	res.Got = b
+	Record(res, step, "POST", url, ghUsername, bodystr, resp.StatusCode, b, nil)
 
 	// check expected status code
 	if resp.StatusCode != code {
@@ -28,6 +28,7 @@ func Put(res *testresult.TestResult, step string, url string, bodystr string, co
 	AddAuthHeader(res, step, req, ghUsername)
 	resp, err := client.Do(req)
 	if err != nil {
+		Record(res, step, "PUT", url, ghUsername, bodystr, 0, nil, err)
 		FailTest(res, step, err)
 		return err
 	}
@@ -42,6 +43,7 @@ func Put(res *testresult.TestResult, step string, url string, bodystr string, co
 
 	// record in testresult
 	res.Got = b
+	Record(res, step, "PUT", url, ghUsername, bodystr, resp.StatusCode, b, nil)
 
 	// check expected status code
 	if resp.StatusCode != code {
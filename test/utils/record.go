@@ -0,0 +1,50 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package utils
+
+import (
+	"sync"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// maxRecordedBody is the most of a response body that is kept in
+// a test's transcript, so that large list responses do not fill
+// up memory.
+const maxRecordedBody = 64 << 10
+
+// recordMu guards the transcripts, since tests such as the
+// concurrency tests make several requests at once.
+var recordMu sync.Mutex
+
+// Record adds a request and its response to the test's
+// transcript. status is 0 and err is set if no response was
+// received. The helpers, including Send, call it for each
+// request; tests that make requests some other way can call it
+// themselves.
+func Record(res *testresult.TestResult, step string, method string, url string, ghUsername string, reqBody string, status int, respBody []byte, err error) {
+	if res == nil {
+		return
+	}
+
+	ex := &testresult.Exchange{
+		Step:        step,
+		Method:      method,
+		URL:         url,
+		User:        ghUsername,
+		RequestBody: reqBody,
+		Status:      status,
+	}
+	if len(respBody) > maxRecordedBody {
+		respBody = respBody[:maxRecordedBody]
+		ex.Truncated = true
+	}
+	ex.ResponseBody = append([]byte{}, respBody...)
+	if err != nil {
+		ex.Error = err.Error()
+	}
+
+	recordMu.Lock()
+	res.Transcript = append(res.Transcript, ex)
+	recordMu.Unlock()
+}
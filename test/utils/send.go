@@ -0,0 +1,52 @@
+// SPDX-License-Identifier: Apache-2.0 OR GPL-2.0-or-later
+
+package utils
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/swinslow/peridot-api-testing/internal/testresult"
+)
+
+// Send makes an HTTP call with the indicated method to the
+// indicated URL, with the specified body text if it is not empty.
+// Unlike the other helpers, it does not check the status code or
+// fail the test; it returns the status code and the response body
+// for the caller to check. It is primarily useful when many calls
+// are made at once, such as in concurrency tests. The call is
+// recorded in the test's transcript as the given step; res may be
+// nil for a call that is not part of a test.
+func Send(res *testresult.TestResult, step string, method string, url string, bodystr string, ghUsername string) (int, []byte, error) {
+	code, b, err := send(method, url, bodystr, ghUsername)
+	Record(res, step, method, url, ghUsername, bodystr, code, b, err)
+	return code, b, err
+}
+
+func send(method string, url string, bodystr string, ghUsername string) (int, []byte, error) {
+	var body io.Reader
+	if bodystr != "" {
+		body = strings.NewReader(bodystr)
+	}
+
+	client := &http.Client{}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	AddAuthHeader(nil, "0", req, ghUsername)
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	b, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, err
+	}
+
+	return resp.StatusCode, b, nil
+}
@@ -3,9 +3,12 @@
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/swinslow/peridot-api-testing/client"
 	"github.com/swinslow/peridot-api-testing/internal/testresult"
 	"github.com/yudai/gojsondiff"
 )
@@ -24,6 +27,15 @@ func FailTest(res *testresult.TestResult, step string, msg error) {
 	res.FailError = msg
 }
 
+// Skip marks a test as skipped, for a test that cannot run, e.g.
+// because something it needs is missing. The test should return
+// right after.
+func Skip(res *testresult.TestResult, reason string) {
+	res.Success = false
+	res.Skipped = true
+	res.Reason = reason
+}
+
 // FailMatch fills in the failure fields for a test that failed
 // because the desired JSON string did not match the JSON string
 // that was received.
@@ -36,7 +48,19 @@ func FailMatch(res *testresult.TestResult, step string) {
 // JSON data, and returns a bool indicating whether they contained
 // equivalent content. It will also return "false" if there is e.g.
 // an error with the JSON unmarshalling, etc.
+// If UpdateGolden is set and the wanted string came from a golden
+// file, a mismatch is recorded as an update to that file and
+// "true" is returned, so that the rest of the test still runs;
+// CheckGolden fails the test once it is done.
 func IsMatch(res *testresult.TestResult) bool {
+	defer forgetGolden(res)
+	if isMatch(res) {
+		return true
+	}
+	return UpdateGolden && recordGoldenMismatch(res)
+}
+
+func isMatch(res *testresult.TestResult) bool {
 	differ := gojsondiff.New()
 	d, err := differ.Compare([]byte(res.Wanted), res.Got)
 	// fmt.Printf("*** WANTED:  %#v\n", wanted)
@@ -82,3 +106,68 @@ func AddAuthHeader(res *testresult.TestResult, step string, req *http.Request, g
 		}
 	}
 }
+
+// IsError checks whether the got byte slice is a JSON object with
+// a non-empty "error" string, as the API returns for failed
+// requests. The exact message is not checked.
+func IsError(res *testresult.TestResult) bool {
+	var e struct {
+		Error *string `json:"error"`
+	}
+	err := json.Unmarshal(res.Got, &e)
+	if err != nil {
+		return false
+	}
+
+	return e.Error != nil && *e.Error != ""
+}
+
+// Identity returns a client.Identity that authenticates as the
+// given test user, with the same names as AddAuthHeader.
+func Identity(ghUsername string) client.Identity {
+	return client.IdentityFunc(func(req *http.Request) error {
+		if !KnownUser(ghUsername) {
+			return fmt.Errorf("invalid username %s", ghUsername)
+		}
+		AddAuthHeader(nil, "", req, ghUsername)
+		return nil
+	})
+}
+
+// KnownUser returns whether AddAuthHeader knows the github
+// username, including "none" for sending no token.
+func KnownUser(ghUsername string) bool {
+	switch ghUsername {
+	case "none", "admin", "operator", "commenter", "viewer", "disabled":
+		return true
+	}
+	return false
+}
+
+// leakMarkers are strings that should never appear in a response,
+// since they indicate an internal error or file contents leaking
+// out.
+var leakMarkers = []string{
+	"pq: ",
+	"sql: ",
+	"syntax error",
+	"panic",
+	"goroutine ",
+	"runtime error",
+	".go:",
+	"root:x:0:0",
+	"[extensions]",
+}
+
+// FindLeak checks a response body for signs of internal details,
+// such as SQL errors or stack traces, and returns the first one
+// found, or "" if there are none.
+func FindLeak(b []byte) string {
+	lower := strings.ToLower(string(b))
+	for _, m := range leakMarkers {
+		if strings.Contains(lower, m) {
+			return m
+		}
+	}
+	return ""
+}